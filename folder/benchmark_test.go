@@ -0,0 +1,137 @@
+package folder_test
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+)
+
+// go test -run xxx -bench . -benchmem ./folder
+var benchmarkSizes = []int{1_000, 100_000, 1_000_000}
+
+var (
+	benchmarkMu   sync.Mutex
+	benchmarkData = map[int][]folder.Folder{}
+)
+
+// Generates (once) a deterministic dataset with n folders spread across 3 orgs
+func benchmarkFolders(b *testing.B, n int) []folder.Folder {
+	b.Helper()
+	benchmarkMu.Lock()
+	defer benchmarkMu.Unlock()
+
+	if _, exists := benchmarkData[n]; !exists {
+		benchmarkData[n] = folder.Generate(
+			folder.WithRoots(12),
+			folder.WithMaxChild(12),
+			folder.WithMaxDepth(8),
+			folder.WithOrgs(3),
+			folder.WithLimit(n),
+			folder.WithSeed(1),
+		)
+	}
+	return benchmarkData[n]
+}
+
+// Picks a second level folder and a root of the same org it can be moved to
+func benchmarkMove(folders []folder.Folder) (src folder.Folder, dst folder.Folder) {
+	for _, f := range folders {
+		if strings.Count(f.Paths, ".") == 1 {
+			src = f
+			break
+		}
+	}
+	for _, f := range folders {
+		if f.OrgId == src.OrgId && !strings.Contains(f.Paths, ".") && !strings.HasPrefix(src.Paths, f.Paths+".") {
+			dst = f
+			break
+		}
+	}
+	return src, dst
+}
+
+func Benchmark_folder_NewDriver(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+			folders := benchmarkFolders(b, size)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				folder.NewDriver(folders)
+			}
+		})
+	}
+}
+
+func Benchmark_folder_GetFoldersByOrgID(b *testing.B) {
+	orgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+			driver := folder.NewDriver(benchmarkFolders(b, size))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				driver.GetFoldersByOrgID(orgID)
+			}
+		})
+	}
+}
+
+func Benchmark_folder_GetAllChildFolders(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+			folders := benchmarkFolders(b, size)
+			src, _ := benchmarkMove(folders)
+			driver := folder.NewDriver(folders)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := driver.GetAllChildFolders(src.OrgId, src.Paths); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func Benchmark_folder_MoveFolder(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+			folders := benchmarkFolders(b, size)
+			src, dst := benchmarkMove(folders)
+			driver := folder.NewDriver(folders)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := driver.MoveFolder(src.OrgId, src.Paths, dst.Paths); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// A batch of 20 moves, the source goes back and forth between its parent and another root
+func Benchmark_folder_MoveFolders(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+			folders := benchmarkFolders(b, size)
+			src, dst := benchmarkMove(folders)
+			parent := src.Paths[:strings.Index(src.Paths, ".")]
+			requests := []folder.MoveRequest{}
+			for i := 0; i < 10; i++ {
+				requests = append(requests,
+					folder.MoveRequest{Name: parent + "." + src.Name, Dst: dst.Paths},
+					folder.MoveRequest{Name: dst.Paths + "." + src.Name, Dst: parent},
+				)
+			}
+			driver := folder.NewDriver(folders)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := driver.MoveFolders(src.OrgId, requests); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package folder
+
+import (
+	"fmt"
+
+	"github.com/gofrs/uuid"
+)
+
+// CollisionPolicy decides what happens when the destination already has a folder with the copied name.
+type CollisionPolicy int
+
+const (
+	// CollisionFail rejects the copy with ErrNameConflict.
+	CollisionFail CollisionPolicy = iota
+	// CollisionSuffix copies to the first free name of the form "name-2", "name-3", ...
+	CollisionSuffix
+	// CollisionMerge copies into the existing folder, folders that already exist at a copied path are kept as is.
+	CollisionMerge
+)
+
+// CopyOption configures CopyFolder.
+type CopyOption func(*copyOptions)
+
+type copyOptions struct {
+	policy    CollisionPolicy
+	targetOrg uuid.UUID
+}
+
+// CopyWithPolicy sets how name collisions at the destination are resolved, the default is CollisionFail
+func CopyWithPolicy(policy CollisionPolicy) CopyOption {
+	return func(o *copyOptions) { o.policy = policy }
+}
+
+// CopyToOrg allows copying into another organization, dstPath is then resolved in orgID
+func CopyToOrg(orgID uuid.UUID) CopyOption {
+	return func(o *copyOptions) { o.targetOrg = orgID }
+}
+
+// A method to duplicate the folder at srcPath and all its children below the folder at dstPath.
+// An empty dstPath copies to the root of the organization. Unlike MoveFolder, copies across
+// organizations are allowed when the target organization is given with CopyToOrg.
+// Returns every folder with the copies appended after them, in the order of the copied subtree.
+func (f *driver) CopyFolder(orgID uuid.UUID, srcPath string, dstPath string, opts ...CopyOption) ([]Folder, error) {
+	defer f.lockForChange()()
+
+	options := copyOptions{policy: CollisionFail, targetOrg: orgID}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.targetOrg == uuid.Nil {
+		return nil, &FolderError{Op: "copy", Path: srcPath, Err: ErrInvalidOrgID}
+	}
+
+	srcIdx, err := f.lookupPath("copy", orgID, srcPath)
+	if err != nil {
+		return nil, err
+	}
+	sourceFolder := f.folders[srcIdx]
+
+	parentIdx := -1
+	if dstPath != "" {
+		parentIdx, err = f.lookupPath("copy", options.targetOrg, dstPath)
+		if err != nil {
+			// Same as MoveFolder, a destination in another organization needs CopyToOrg
+			if options.targetOrg == orgID && f.existsInOtherOrg(orgID, dstPath) {
+				return nil, newFolderError("copy", sourceFolder, ErrCrossOrg)
+			}
+			return nil, err
+		}
+	}
+
+	// Resolve a collision of the copied folder with an existing child of the destination
+	rootName := sourceFolder.Name
+	if _, exists := f.pathMap[pathKey(options.targetOrg, joinPath(dstPath, rootName))]; exists {
+		switch options.policy {
+		case CollisionFail:
+			return nil, newFolderError("copy", sourceFolder, ErrNameConflict)
+		case CollisionSuffix:
+			rootName = f.freeName(options.targetOrg, dstPath, sourceFolder.Name)
+			if err := ValidateLabel(rootName); err != nil {
+				return nil, newFolderError("copy", sourceFolder, err)
+			}
+		case CollisionMerge:
+		default:
+			return nil, newFolderError("copy", sourceFolder, ErrInvalidMode)
+		}
+	}
+	rootPath := joinPath(dstPath, rootName)
+
+	// The copied folder goes after its new siblings, its children keep their positions
+	rootPosition := 0
+	if parentIdx != -1 {
+		for _, sibling := range f.nodes[parentIdx].children {
+			rootPosition = max(rootPosition, f.folders[sibling.idx].Position)
+		}
+	}
+
+	copies := []Folder{}
+	for _, idx := range append([]int{srcIdx}, f.subtree(f.nodes[srcIdx], 0)...) {
+		folder := f.folders[idx]
+		folder.OrgId = options.targetOrg
+		folder.Paths = rebasePath(folder.Paths, sourceFolder.Paths, rootPath)
+		if idx == srcIdx {
+			folder.Name = rootName
+			folder.Position = rootPosition
+		}
+
+		// Only reachable with CollisionMerge, the existing folder is kept
+		if _, exists := f.pathMap[pathKey(folder.OrgId, folder.Paths)]; exists {
+			continue
+		}
+		copies = append(copies, folder)
+	}
+
+	f.track(keepAll, len(copies))
+	newFolders := make([]Folder, len(f.folders), len(f.folders)+len(copies))
+	copy(newFolders, f.folders)
+	return f.commit(append(newFolders, copies...)), nil
+}
+
+// Returns the first "name-N" (N starting at 2) that is not used by a child of parentPath
+func (f *driver) freeName(orgID uuid.UUID, parentPath string, name string) string {
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s-%d", name, n)
+		if _, exists := f.pathMap[pathKey(orgID, joinPath(parentPath, candidate))]; !exists {
+			return candidate
+		}
+	}
+}
+
+// Helper function to build the path of a child called name, an empty parentPath means a root
+func joinPath(parentPath, name string) string {
+	if parentPath == "" {
+		return name
+	}
+	return parentPath + "." + name
+}
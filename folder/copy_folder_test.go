@@ -0,0 +1,156 @@
+package folder_test
+
+import (
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_CopyFolder(t *testing.T) {
+	folders := []folder.Folder{
+		{Name: "template", Paths: "template", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "reports", Paths: "template.reports", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "daily", Paths: "template.reports.daily", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "weekly", Paths: "template.reports.weekly", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "site", Paths: "site", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "reports", Paths: "site.reports", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "daily", Paths: "site.reports.daily", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "foxtrot", Paths: "foxtrot", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+	}
+
+	testCases := []struct {
+		name       string
+		orgID      uuid.UUID
+		src        string
+		dst        string
+		opts       []folder.CopyOption
+		wantCopies []folder.Folder // folders added after the original ones
+		wantErr    error
+	}{
+		{
+			name: "Copy subtree to a new location",
+			src:  "template.reports",
+			dst:  "template.reports.weekly",
+			wantCopies: []folder.Folder{
+				{Name: "reports", Paths: "template.reports.weekly.reports", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "daily", Paths: "template.reports.weekly.reports.daily", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "weekly", Paths: "template.reports.weekly.reports.weekly", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+			},
+		},
+		{
+			name: "Copy to the root",
+			src:  "site.reports",
+			dst:  "",
+			wantCopies: []folder.Folder{
+				{Name: "reports", Paths: "reports", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "daily", Paths: "reports.daily", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+			},
+		},
+		{
+			name:    "Collision fails by default",
+			src:     "template.reports",
+			dst:     "site",
+			wantErr: folder.ErrNameConflict,
+		},
+		{
+			name: "Collision with suffix",
+			src:  "template.reports",
+			dst:  "site",
+			opts: []folder.CopyOption{folder.CopyWithPolicy(folder.CollisionSuffix)},
+			wantCopies: []folder.Folder{
+				{Name: "reports-2", Paths: "site.reports-2", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "daily", Paths: "site.reports-2.daily", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "weekly", Paths: "site.reports-2.weekly", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+			},
+		},
+		{
+			name: "Collision with merge",
+			src:  "template.reports",
+			dst:  "site",
+			opts: []folder.CopyOption{folder.CopyWithPolicy(folder.CollisionMerge)},
+			wantCopies: []folder.Folder{
+				{Name: "weekly", Paths: "site.reports.weekly", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+			},
+		},
+		{
+			name:    "Destination in another organization",
+			src:     "template",
+			dst:     "foxtrot",
+			wantErr: folder.ErrCrossOrg,
+		},
+		{
+			name: "Copy to another organization when allowed",
+			src:  "template.reports",
+			dst:  "foxtrot",
+			opts: []folder.CopyOption{folder.CopyToOrg(uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b"))},
+			wantCopies: []folder.Folder{
+				{Name: "reports", Paths: "foxtrot.reports", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+				{Name: "daily", Paths: "foxtrot.reports.daily", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+				{Name: "weekly", Paths: "foxtrot.reports.weekly", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+			},
+		},
+		{
+			name:    "Destination missing in the target organization",
+			src:     "template",
+			dst:     "site",
+			opts:    []folder.CopyOption{folder.CopyToOrg(uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b"))},
+			wantErr: folder.ErrFolderNotFound,
+		},
+		{
+			name:    "Source does not exist",
+			src:     "template.zulu",
+			dst:     "site",
+			wantErr: folder.ErrFolderNotFound,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			orgID := uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")
+			result, error := folder.NewDriver(folders).CopyFolder(orgID, test.src, test.dst, test.opts...)
+
+			if test.wantErr != nil {
+				assert.ErrorIs(t, error, test.wantErr)
+				return
+			}
+			assert.NoError(t, error)
+			assert.Equal(t, append(append([]folder.Folder(nil), folders...), test.wantCopies...), result)
+
+			// A stateful driver can query the copies
+			driver := folder.NewStatefulDriver(folders)
+			_, error = driver.CopyFolder(orgID, test.src, test.dst, test.opts...)
+			assert.NoError(t, error)
+			for _, f := range test.wantCopies {
+				got, err := driver.GetFolderByPath(f.OrgId, f.Paths)
+				assert.NoError(t, err)
+				assert.Equal(t, f, got)
+			}
+		})
+	}
+}
+
+// The copy is placed after the destination children, the children inside the copy keep their order
+func Test_folder_CopyFolder_Position(t *testing.T) {
+	orgID := uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")
+	driver := folder.NewStatefulDriver([]folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: orgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: orgID, Position: 1},
+		{Name: "charlie", Paths: "alpha.charlie", OrgId: orgID, Position: 0},
+		{Name: "golf", Paths: "golf", OrgId: orgID},
+		{Name: "hotel", Paths: "golf.hotel", OrgId: orgID, Position: 3},
+	})
+
+	_, err := driver.CopyFolder(orgID, "alpha", "golf")
+	assert.NoError(t, err)
+
+	children, err := driver.GetAllChildFolders(orgID, "golf")
+	assert.NoError(t, err)
+	assert.Equal(t, []folder.Folder{
+		{Name: "hotel", Paths: "golf.hotel", OrgId: orgID, Position: 3},
+		{Name: "alpha", Paths: "golf.alpha", OrgId: orgID, Position: 3},
+		{Name: "charlie", Paths: "golf.alpha.charlie", OrgId: orgID, Position: 0},
+		{Name: "bravo", Paths: "golf.alpha.bravo", OrgId: orgID, Position: 1},
+	}, children)
+}
@@ -0,0 +1,76 @@
+package folder
+
+import (
+	"github.com/gofrs/uuid"
+)
+
+// MaxLabelLength is the longest folder name accepted, same as the ltree label limit in PostgreSQL.
+const MaxLabelLength = 1000
+
+// ValidateLabel checks that name can be used as a single ltree label:
+// 1 to MaxLabelLength characters, only letters, digits, underscores and hyphens (so no dots).
+func ValidateLabel(name string) error {
+	if name == "" {
+		return ErrInvalidName
+	}
+	if len(name) > MaxLabelLength {
+		return ErrInvalidLabel
+	}
+	for _, c := range name {
+		if !isLabelChar(c) {
+			return ErrInvalidLabel
+		}
+	}
+	return nil
+}
+
+func isLabelChar(c rune) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+// A method to create a new folder called name below the folder at parentPath.
+// An empty parentPath creates a new root folder in the organization.
+// Returns every folder with the new one appended last.
+func (f *driver) CreateFolder(orgID uuid.UUID, parentPath string, name string) ([]Folder, error) {
+	defer f.lockForChange()()
+
+	if orgID == uuid.Nil {
+		return nil, &FolderError{Op: "create", Name: name, Err: ErrInvalidOrgID}
+	}
+	if err := ValidateLabel(name); err != nil {
+		return nil, &FolderError{Op: "create", Name: name, OrgID: orgID, Err: err}
+	}
+
+	path := name
+	parentIdx := -1
+	if parentPath != "" {
+		idx, err := f.lookupPath("create", orgID, parentPath)
+		if err != nil {
+			return nil, err
+		}
+		parentIdx = idx
+		path = parentPath + "." + name
+	}
+
+	// Reject duplicates under the same parent
+	folder := Folder{Name: name, OrgId: orgID, Paths: path}
+	if _, exists := f.pathMap[pathKey(orgID, path)]; exists {
+		return nil, newFolderError("create", folder, ErrNameConflict)
+	}
+
+	// New folders go after their siblings: same position as the last one, and a later slice index
+	if parentIdx != -1 {
+		for _, sibling := range f.nodes[parentIdx].children {
+			folder.Position = max(folder.Position, f.folders[sibling.idx].Position)
+		}
+	}
+
+	if f.stateful {
+		f.insert(folder, parentIdx)
+		return append([]Folder(nil), f.folders...), nil
+	}
+
+	newFolders := make([]Folder, len(f.folders), len(f.folders)+1)
+	copy(newFolders, f.folders)
+	return append(newFolders, folder), nil
+}
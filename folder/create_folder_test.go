@@ -0,0 +1,173 @@
+package folder_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_CreateFolder(t *testing.T) {
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "foxtrot", Paths: "foxtrot", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+	}
+
+	testCases := []struct {
+		name       string
+		orgID      uuid.UUID
+		parentPath string
+		folder     string
+		want       folder.Folder
+		wantErr    error
+	}{
+		{
+			name:       "Nested folder",
+			orgID:      uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			parentPath: "alpha.bravo",
+			folder:     "charlie",
+			want:       folder.Folder{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		},
+		{
+			name:       "Root folder",
+			orgID:      uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			parentPath: "",
+			folder:     "golf",
+			want:       folder.Folder{Name: "golf", Paths: "golf", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		},
+		{
+			name:       "Same name in another organization",
+			orgID:      uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b"),
+			parentPath: "foxtrot",
+			folder:     "bravo",
+			want:       folder.Folder{Name: "bravo", Paths: "foxtrot.bravo", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+		},
+		{
+			name:       "Codename with hyphens",
+			orgID:      uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			parentPath: "alpha",
+			folder:     "creative-scalphunter_2",
+			want:       folder.Folder{Name: "creative-scalphunter_2", Paths: "alpha.creative-scalphunter_2", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		},
+		{
+			name:       "Duplicate under the same parent",
+			orgID:      uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			parentPath: "alpha",
+			folder:     "bravo",
+			wantErr:    folder.ErrNameConflict,
+		},
+		{
+			name:       "Duplicate root",
+			orgID:      uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			parentPath: "",
+			folder:     "alpha",
+			wantErr:    folder.ErrNameConflict,
+		},
+		{
+			name:       "Missing parent",
+			orgID:      uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			parentPath: "alpha.zulu",
+			folder:     "charlie",
+			wantErr:    folder.ErrFolderNotFound,
+		},
+		{
+			name:       "Parent in another organization",
+			orgID:      uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			parentPath: "foxtrot",
+			folder:     "charlie",
+			wantErr:    folder.ErrFolderNotFound,
+		},
+		{
+			name:       "Name with a dot",
+			orgID:      uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			parentPath: "alpha",
+			folder:     "charlie.delta",
+			wantErr:    folder.ErrInvalidLabel,
+		},
+		{
+			name:       "Name with a space",
+			orgID:      uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			parentPath: "alpha",
+			folder:     "charlie delta",
+			wantErr:    folder.ErrInvalidLabel,
+		},
+		{
+			name:       "Name too long",
+			orgID:      uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			parentPath: "alpha",
+			folder:     strings.Repeat("a", folder.MaxLabelLength+1),
+			wantErr:    folder.ErrInvalidLabel,
+		},
+		{
+			name:       "Empty name",
+			orgID:      uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			parentPath: "alpha",
+			folder:     "",
+			wantErr:    folder.ErrInvalidName,
+		},
+		{
+			name:       "Missing orgID",
+			orgID:      uuid.Nil,
+			parentPath: "alpha",
+			folder:     "charlie",
+			wantErr:    folder.ErrInvalidOrgID,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			driver := folder.NewDriver(folders)
+			result, error := driver.CreateFolder(test.orgID, test.parentPath, test.folder)
+
+			if test.wantErr != nil {
+				assert.ErrorIs(t, error, test.wantErr)
+				return
+			}
+			assert.NoError(t, error)
+			assert.Equal(t, append(append([]folder.Folder(nil), folders...), test.want), result)
+
+			// A stateless driver doesn't keep the new folder
+			_, err := driver.GetFolderByPath(test.orgID, test.want.Paths)
+			assert.ErrorIs(t, err, folder.ErrFolderNotFound)
+		})
+	}
+}
+
+// A stateful driver can query and build on the folders it created
+func Test_folder_CreateFolder_Stateful(t *testing.T) {
+	orgID := uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")
+	driver := folder.NewStatefulDriver([]folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: orgID},
+	})
+
+	for _, create := range [][2]string{{"alpha", "bravo"}, {"alpha.bravo", "charlie"}, {"", "golf"}} {
+		_, err := driver.CreateFolder(orgID, create[0], create[1])
+		assert.NoError(t, err)
+	}
+
+	children, err := driver.GetAllChildFolders(orgID, "alpha")
+	assert.NoError(t, err)
+	assert.Equal(t, []folder.Folder{
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: orgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: orgID},
+	}, children)
+
+	parent, err := driver.GetParent(orgID, "charlie")
+	assert.NoError(t, err)
+	assert.Equal(t, "alpha.bravo", parent.Paths)
+
+	result, err := driver.MoveFolder(orgID, "bravo", "golf")
+	assert.NoError(t, err)
+	assert.Equal(t, []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: orgID},
+		{Name: "bravo", Paths: "golf.bravo", OrgId: orgID},
+		{Name: "charlie", Paths: "golf.bravo.charlie", OrgId: orgID},
+		{Name: "golf", Paths: "golf", OrgId: orgID},
+	}, result)
+
+	_, err = driver.CreateFolder(orgID, "golf", "bravo")
+	assert.ErrorIs(t, err, folder.ErrNameConflict)
+}
@@ -0,0 +1,158 @@
+package folder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// SampleDataEnv is the environment variable overriding the file used by GetSampleData and WriteSampleData
+const SampleDataEnv = "FOLDER_SAMPLE_DATA"
+
+// SampleDataPath returns the file used by GetSampleData and WriteSampleData:
+// the path in $FOLDER_SAMPLE_DATA when set, otherwise sample.json next to this source file,
+// which only exists when the binary runs from the source tree.
+func SampleDataPath() string {
+	if path := os.Getenv(SampleDataEnv); path != "" {
+		return path
+	}
+	_, filename, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(filename), "sample.json")
+}
+
+// ReadFolders decodes a JSON array of folders, in the format of sample.json
+func ReadFolders(r io.Reader) ([]Folder, error) {
+	folders := []Folder{}
+	if err := json.NewDecoder(r).Decode(&folders); err != nil {
+		return nil, fmt.Errorf("read folders: %w", err)
+	}
+	return folders, nil
+}
+
+// WriteFolders encodes data as indented JSON, in the format of sample.json
+func WriteFolders(w io.Writer, data interface{}) error {
+	b, err := json.MarshalIndent(data, "", "\t")
+	if err != nil {
+		return fmt.Errorf("write folders: %w", err)
+	}
+	if _, err := w.Write(b); err != nil {
+		return fmt.Errorf("write folders: %w", err)
+	}
+	return nil
+}
+
+// LoadFolders reads the folders of the JSON file at path
+func LoadFolders(path string) ([]Folder, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	folders, err := ReadFolders(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return folders, nil
+}
+
+// BackupSuffix is appended to the path of the previous file kept by SaveFolders with WithBackup
+const BackupSuffix = ".bak"
+
+// SaveOption configures SaveFolders.
+type SaveOption func(*saveOptions)
+
+type saveOptions struct {
+	perm   fs.FileMode
+	backup bool
+}
+
+// WithPerm sets the permissions of the written file, the default is 0644
+func WithPerm(perm fs.FileMode) SaveOption {
+	return func(o *saveOptions) { o.perm = perm }
+}
+
+// WithBackup keeps the previous content of the file, if any, at path + BackupSuffix.
+// The backup keeps the permissions of the previous file.
+func WithBackup() SaveOption {
+	return func(o *saveOptions) { o.backup = true }
+}
+
+// SaveFolders writes data to the JSON file at path, replacing the file if it exists.
+// The write is atomic: data goes to a temporary file in the same directory which is synced and then
+// renamed over path, so a crash leaves either the previous file or the new one, never a truncated file.
+func SaveFolders(path string, data interface{}, opts ...SaveOption) error {
+	options := saveOptions{perm: 0644}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	var b bytes.Buffer
+	if err := WriteFolders(&b, data); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	if options.backup {
+		info, err := os.Stat(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		if err == nil {
+			previous, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if err := writeFileAtomic(path+BackupSuffix, previous, info.Mode().Perm()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return writeFileAtomic(path, b.Bytes(), options.perm)
+}
+
+// Writes b to a temporary file next to path, syncs it and renames it to path.
+// The temporary file is removed if anything fails.
+func writeFileAtomic(path string, b []byte, perm fs.FileMode) (err error) {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err = tmp.Write(b); err != nil {
+		return err
+	}
+	// CreateTemp uses 0600, Chmod isn't affected by the umask
+	if err = tmp.Chmod(perm); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	// Sync the directory so the rename itself survives a crash, not every platform supports it
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
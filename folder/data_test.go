@@ -0,0 +1,139 @@
+package folder_test
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_ReadWriteFolders(t *testing.T) {
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"), Position: 1},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, folder.WriteFolders(&buf, folders))
+	assert.Equal(t, string(folder.MarshalJson(folders)), buf.String())
+
+	read, err := folder.ReadFolders(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, folders, read)
+
+	_, err = folder.ReadFolders(strings.NewReader(`{"name": "alpha"}`))
+	assert.Error(t, err)
+}
+
+func Test_folder_LoadSaveFolders(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "folders.json")
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+	}
+
+	_, err := folder.LoadFolders(path)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	assert.NoError(t, folder.SaveFolders(path, folders))
+	loaded, err := folder.LoadFolders(path)
+	assert.NoError(t, err)
+	assert.Equal(t, folders, loaded)
+
+	assert.Error(t, folder.SaveFolders(filepath.Join(path, "not-a-directory.json"), folders))
+}
+
+// The environment variable moves the sample data used by GetSampleData and WriteSampleData
+func Test_folder_SampleDataPath(t *testing.T) {
+	assert.Equal(t, "sample.json", filepath.Base(folder.SampleDataPath()))
+
+	path := filepath.Join(t.TempDir(), "folders.json")
+	t.Setenv(folder.SampleDataEnv, path)
+	assert.Equal(t, path, folder.SampleDataPath())
+
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+	}
+	folder.WriteSampleData(folders)
+	assert.Equal(t, folders, folder.GetSampleData())
+}
+
+func Test_folder_SaveFolders_Options(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "folders.json")
+	before := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+	}
+	after := append(before, folder.Folder{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")})
+
+	// Default permissions
+	assert.NoError(t, folder.SaveFolders(path, before))
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, fs.FileMode(0644), info.Mode().Perm())
+
+	// No backup unless asked for
+	assert.NoError(t, folder.SaveFolders(path, before))
+	_, err = os.Stat(path + folder.BackupSuffix)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	// The previous file is kept as a backup
+	assert.NoError(t, folder.SaveFolders(path, after, folder.WithBackup(), folder.WithPerm(0600)))
+	info, err = os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, fs.FileMode(0600), info.Mode().Perm())
+
+	loaded, err := folder.LoadFolders(path)
+	assert.NoError(t, err)
+	assert.Equal(t, after, loaded)
+	backup, err := folder.LoadFolders(path + folder.BackupSuffix)
+	assert.NoError(t, err)
+	assert.Equal(t, before, backup)
+	info, err = os.Stat(path + folder.BackupSuffix)
+	assert.NoError(t, err)
+	assert.Equal(t, fs.FileMode(0644), info.Mode().Perm())
+
+	// The backup keeps the permissions of the previous file, not the new ones
+	assert.NoError(t, folder.SaveFolders(path, before, folder.WithBackup()))
+	info, err = os.Stat(path + folder.BackupSuffix)
+	assert.NoError(t, err)
+	assert.Equal(t, fs.FileMode(0600), info.Mode().Perm())
+	backup, err = folder.LoadFolders(path + folder.BackupSuffix)
+	assert.NoError(t, err)
+	assert.Equal(t, after, backup)
+
+	// Only the data file and its backup are left, no temporary file
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+// A failed write leaves the previous file untouched and no temporary file behind
+func Test_folder_SaveFolders_Failure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "folders.json")
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+	}
+	assert.NoError(t, folder.SaveFolders(path, folders))
+
+	// Data that can't be encoded
+	assert.Error(t, folder.SaveFolders(path, make(chan int)))
+
+	// The rename fails when the target is a directory
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "taken"), 0755))
+	assert.Error(t, folder.SaveFolders(filepath.Join(dir, "taken"), folders))
+
+	loaded, err := folder.LoadFolders(path)
+	assert.NoError(t, err)
+	assert.Equal(t, folders, loaded)
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
@@ -0,0 +1,84 @@
+package folder
+
+import (
+	"github.com/gofrs/uuid"
+)
+
+// DeleteMode decides what happens to the child folders of a deleted folder.
+type DeleteMode int
+
+const (
+	// DeleteRestrict fails with ErrHasChildren if the folder has child folders.
+	DeleteRestrict DeleteMode = iota
+	// DeleteCascade removes the folder and all its child folders.
+	DeleteCascade
+	// DeleteReparent removes the folder only, its children are lifted to the folder's parent.
+	DeleteReparent
+)
+
+// A method to delete the folder at path, returning every folder that was removed
+// and the new folder structure, where children lifted by DeleteReparent have their new paths.
+func (f *driver) DeleteFolder(orgID uuid.UUID, path string, mode DeleteMode) (removed []Folder, folders []Folder, err error) {
+	defer f.lockForChange()()
+
+	idx, err := f.lookupPath("delete", orgID, path)
+	if err != nil {
+		return nil, nil, err
+	}
+	deleted := f.folders[idx]
+	children := f.subtree(f.nodes[idx], 0)
+
+	removedIdx := map[int]bool{idx: true}
+	newPaths := map[int]string{}
+
+	switch mode {
+	case DeleteRestrict:
+		if len(children) > 0 {
+			return nil, nil, newFolderError("delete", deleted, ErrHasChildren)
+		}
+	case DeleteCascade:
+		for _, child := range children {
+			removedIdx[child] = true
+		}
+	case DeleteReparent:
+		// Lift the children one level up, the paths below them are kept
+		for _, child := range children {
+			newPaths[child] = liftPath(f.folders[child].Paths, deleted.Paths)
+		}
+		// The direct children must not clash with the siblings of the deleted folder,
+		// the deleted folder itself frees its path so a child with the same name can take it
+		for _, child := range f.nodes[idx].children {
+			if clash, exists := f.pathMap[pathKey(orgID, newPaths[child.idx])]; exists && clash != idx {
+				return nil, nil, newFolderError("delete", f.folders[child.idx], ErrNameConflict)
+			}
+		}
+	default:
+		return nil, nil, newFolderError("delete", deleted, ErrInvalidMode)
+	}
+
+	newFolders := make([]Folder, 0, len(f.folders)-len(removedIdx))
+	removed = []Folder{}
+	for i, folder := range f.folders {
+		if removedIdx[i] {
+			removed = append(removed, folder)
+			continue
+		}
+		if newPath, exists := newPaths[i]; exists {
+			folder.Paths = newPath
+		}
+		newFolders = append(newFolders, folder)
+	}
+
+	f.track(func(idx int) bool { return !removedIdx[idx] }, 0)
+	return removed, f.commit(newFolders), nil
+}
+
+// Helper function to remove the last label of removedPath from a path below it,
+// e.g. lifting "alpha.bravo.charlie" out of "alpha.bravo" gives "alpha.charlie".
+func liftPath(path, removedPath string) string {
+	parentPath := parentOf(removedPath)
+	if parentPath == "" {
+		return path[len(removedPath)+1:]
+	}
+	return rebasePath(path, removedPath, parentPath)
+}
@@ -0,0 +1,244 @@
+package folder_test
+
+import (
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_DeleteFolder(t *testing.T) {
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "delta", Paths: "alpha.bravo.charlie.delta", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "echo", Paths: "alpha.echo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "charlie", Paths: "alpha.echo.charlie", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+		{Name: "bravo", Paths: "alpha.bravo.bravo", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+		{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("c1234567-b7c0-45a3-a6ae-9546248fb17c")},
+		{Name: "alpha", Paths: "alpha.alpha", OrgId: uuid.FromStringOrNil("c1234567-b7c0-45a3-a6ae-9546248fb17c")},
+	}
+
+	testCases := []struct {
+		name        string
+		orgID       uuid.UUID
+		path        string
+		mode        folder.DeleteMode
+		wantRemoved []folder.Folder
+		wantFolders []folder.Folder // folders of orgID left after the delete
+		wantErr     error
+	}{
+		{
+			name:  "Restrict - leaf folder",
+			orgID: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			path:  "alpha.bravo.charlie.delta",
+			mode:  folder.DeleteRestrict,
+			wantRemoved: []folder.Folder{
+				{Name: "delta", Paths: "alpha.bravo.charlie.delta", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+			},
+			wantFolders: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "echo", Paths: "alpha.echo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "charlie", Paths: "alpha.echo.charlie", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+			},
+		},
+		{
+			name:    "Restrict - folder with children",
+			orgID:   uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			path:    "alpha.bravo",
+			mode:    folder.DeleteRestrict,
+			wantErr: folder.ErrHasChildren,
+		},
+		{
+			name:  "Cascade - whole subtree",
+			orgID: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			path:  "alpha.bravo",
+			mode:  folder.DeleteCascade,
+			wantRemoved: []folder.Folder{
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "delta", Paths: "alpha.bravo.charlie.delta", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+			},
+			wantFolders: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "echo", Paths: "alpha.echo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "charlie", Paths: "alpha.echo.charlie", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+			},
+		},
+		{
+			name:  "Reparent - children lifted to the parent",
+			orgID: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			path:  "alpha.bravo.charlie",
+			mode:  folder.DeleteReparent,
+			wantRemoved: []folder.Folder{
+				{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+			},
+			wantFolders: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "delta", Paths: "alpha.bravo.delta", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "echo", Paths: "alpha.echo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "charlie", Paths: "alpha.echo.charlie", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+			},
+		},
+		{
+			name:  "Reparent - root folder children become roots",
+			orgID: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b"),
+			path:  "alpha",
+			mode:  folder.DeleteReparent,
+			wantRemoved: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+			},
+			wantFolders: []folder.Folder{
+				{Name: "bravo", Paths: "bravo", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+				{Name: "bravo", Paths: "bravo.bravo", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+			},
+		},
+		{
+			name:  "Reparent - child takes the path of its deleted parent",
+			orgID: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b"),
+			path:  "alpha.bravo",
+			mode:  folder.DeleteReparent,
+			wantRemoved: []folder.Folder{
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+			},
+			wantFolders: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+			},
+		},
+		{
+			name:  "Reparent - child takes the path of its deleted root",
+			orgID: uuid.FromStringOrNil("c1234567-b7c0-45a3-a6ae-9546248fb17c"),
+			path:  "alpha",
+			mode:  folder.DeleteReparent,
+			wantRemoved: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("c1234567-b7c0-45a3-a6ae-9546248fb17c")},
+			},
+			wantFolders: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("c1234567-b7c0-45a3-a6ae-9546248fb17c")},
+			},
+		},
+		{
+			name:  "Reparent - lifted name is used in another branch",
+			orgID: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			path:  "alpha.echo",
+			mode:  folder.DeleteReparent,
+			wantRemoved: []folder.Folder{
+				{Name: "echo", Paths: "alpha.echo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+			},
+			wantFolders: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "delta", Paths: "alpha.bravo.charlie.delta", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "charlie", Paths: "alpha.charlie", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+			},
+		},
+		{
+			name:  "Reparent - nested children keep their subtree",
+			orgID: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			path:  "alpha.bravo",
+			mode:  folder.DeleteReparent,
+			wantRemoved: []folder.Folder{
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+			},
+			wantFolders: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "charlie", Paths: "alpha.charlie", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "delta", Paths: "alpha.charlie.delta", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "echo", Paths: "alpha.echo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "charlie", Paths: "alpha.echo.charlie", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+			},
+		},
+		{
+			name:    "Folder does not exist",
+			orgID:   uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			path:    "alpha.zulu",
+			mode:    folder.DeleteCascade,
+			wantErr: folder.ErrFolderNotFound,
+		},
+		{
+			name:    "Unknown mode",
+			orgID:   uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			path:    "alpha",
+			mode:    folder.DeleteMode(42),
+			wantErr: folder.ErrInvalidMode,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			// A stateless driver returns the same result as a stateful one
+			removed, result, error := folder.NewDriver(folders).DeleteFolder(test.orgID, test.path, test.mode)
+			if test.wantErr == nil {
+				assert.NoError(t, error)
+				assert.Equal(t, test.wantRemoved, removed)
+				assert.Equal(t, test.wantFolders, folder.NewDriver(result).GetFoldersByOrgID(test.orgID))
+			}
+
+			driver := folder.NewStatefulDriver(folders)
+			removed, result, error = driver.DeleteFolder(test.orgID, test.path, test.mode)
+
+			if test.wantErr != nil {
+				assert.ErrorIs(t, error, test.wantErr)
+				assert.Nil(t, result)
+				var got []folder.Folder
+				for _, orgID := range []uuid.UUID{uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"), uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b"), uuid.FromStringOrNil("c1234567-b7c0-45a3-a6ae-9546248fb17c")} {
+					got = append(got, driver.GetFoldersByOrgID(orgID)...)
+				}
+				assert.Equal(t, folders, got)
+				return
+			}
+			assert.NoError(t, error)
+			assert.Equal(t, test.wantRemoved, removed)
+			assert.Equal(t, test.wantFolders, driver.GetFoldersByOrgID(test.orgID))
+			assert.Equal(t, test.wantFolders, folder.NewDriver(result).GetFoldersByOrgID(test.orgID))
+
+			// The indexes follow the new structure
+			for _, f := range test.wantFolders {
+				got, err := driver.GetFolderByPath(test.orgID, f.Paths)
+				assert.NoError(t, err)
+				assert.Equal(t, f, got)
+			}
+			for _, f := range test.wantRemoved {
+				got, err := driver.GetFolderByPath(test.orgID, f.Paths)
+				if err == nil {
+					// A lifted child has taken the path of the removed folder
+					assert.Contains(t, test.wantFolders, got)
+					continue
+				}
+				assert.ErrorIs(t, err, folder.ErrFolderNotFound)
+			}
+		})
+	}
+}
+
+func Test_folder_DeleteFolder_ReparentConflict(t *testing.T) {
+	orgID := uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: orgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: orgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: orgID},
+		{Name: "charlie", Paths: "alpha.charlie", OrgId: orgID},
+	}
+
+	driver := folder.NewStatefulDriver(folders)
+	_, _, err := driver.DeleteFolder(orgID, "alpha.bravo", folder.DeleteReparent)
+	assert.ErrorIs(t, err, folder.ErrNameConflict)
+	assert.Equal(t, folders, driver.GetFoldersByOrgID(orgID))
+
+	// A stateless driver returns the removed folders and the new structure but keeps its data
+	driver = folder.NewDriver(folders)
+	removed, result, err := driver.DeleteFolder(orgID, "alpha", folder.DeleteCascade)
+	assert.NoError(t, err)
+	assert.Equal(t, folders, removed)
+	assert.Empty(t, result)
+	assert.Equal(t, folders, driver.GetFoldersByOrgID(orgID))
+}
@@ -0,0 +1,198 @@
+package folder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/gofrs/uuid"
+)
+
+// FolderDiff is the difference between two folder snapshots, by organization.
+// Organizations without any change are left out.
+type FolderDiff map[uuid.UUID]*OrgDiff
+
+// OrgDiff lists the folders of an organization that changed between two snapshots.
+// Children that only follow a moved or renamed parent are not listed.
+type OrgDiff struct {
+	Added   []PathChange `json:"added,omitempty"`
+	Removed []PathChange `json:"removed,omitempty"`
+	Moved   []PathChange `json:"moved,omitempty"`
+	Renamed []PathChange `json:"renamed,omitempty"`
+}
+
+type changeKind int
+
+const (
+	changeNone changeKind = iota // unchanged, or following its parent
+	changeMoved
+	changeRenamed
+)
+
+// Diff compares two folder snapshots, e.g. two files written by WriteSampleData.
+// Folders have no identity besides their path, so they are matched in this order:
+//   - same path in both snapshots: unchanged
+//   - same name below the new path of its parent: follows a moved or renamed parent, not listed
+//   - the only folder of the organization with that name on both sides: moved
+//   - the only new folder below the same parent on both sides: renamed
+//
+// Folders left in before are removed, folders left in after are added.
+// A folder that is moved and renamed at once is listed as removed and added.
+func Diff(before, after []Folder) FolderDiff {
+	afterByPath := make(map[string]int, len(after))
+	for j, folder := range after {
+		afterByPath[pathKey(folder.OrgId, folder.Paths)] = j
+	}
+
+	matched := make([]int, len(before)) // index in after of every before folder, -1 if not matched yet
+	used := make([]bool, len(after))
+	pending := []int{}
+	for i, folder := range before {
+		matched[i] = -1
+		if j, exists := afterByPath[pathKey(folder.OrgId, folder.Paths)]; exists && !used[j] {
+			matched[i] = j
+			used[j] = true
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	// Index what is left on both sides by name and by parent path
+	afterByName := map[string][]int{}
+	afterByParent := map[string][]int{}
+	for j, folder := range after {
+		if !used[j] {
+			afterByName[nameKey(folder.OrgId, folder.Name)] = append(afterByName[nameKey(folder.OrgId, folder.Name)], j)
+			afterByParent[pathKey(folder.OrgId, parentOf(folder.Paths))] = append(afterByParent[pathKey(folder.OrgId, parentOf(folder.Paths))], j)
+		}
+	}
+	beforeByName := map[string]int{}
+	beforeByParent := map[string]int{}
+	for _, i := range pending {
+		beforeByName[nameKey(before[i].OrgId, before[i].Name)]++
+		beforeByParent[pathKey(before[i].OrgId, parentOf(before[i].Paths))]++
+	}
+	unused := func(candidates []int) []int {
+		res := []int{}
+		for _, j := range candidates {
+			if !used[j] {
+				res = append(res, j)
+			}
+		}
+		return res
+	}
+
+	// Parents first, so children can follow the new path of their parent
+	sort.SliceStable(pending, func(a, b int) bool {
+		return folderDepth(before[pending[a]].Paths) < folderDepth(before[pending[b]].Paths)
+	})
+
+	kinds := make([]changeKind, len(before))
+	newPaths := map[string]string{} // path+orgID in before -> path in after, for the folders whose path changed
+	for _, i := range pending {
+		folder := before[i]
+		parentPath := parentOf(folder.Paths)
+		newParentPath := parentPath
+		if path, exists := newPaths[pathKey(folder.OrgId, parentPath)]; exists {
+			newParentPath = path
+		}
+
+		j := -1
+		if k, exists := afterByPath[pathKey(folder.OrgId, joinPath(newParentPath, folder.Name))]; exists && !used[k] {
+			j = k
+		} else if candidates := unused(afterByName[nameKey(folder.OrgId, folder.Name)]); len(candidates) == 1 && beforeByName[nameKey(folder.OrgId, folder.Name)] == 1 {
+			j = candidates[0]
+			kinds[i] = changeMoved
+		} else if candidates := unused(afterByParent[pathKey(folder.OrgId, newParentPath)]); len(candidates) == 1 && beforeByParent[pathKey(folder.OrgId, parentPath)] == 1 {
+			j = candidates[0]
+			kinds[i] = changeRenamed
+		}
+
+		beforeByName[nameKey(folder.OrgId, folder.Name)]--
+		beforeByParent[pathKey(folder.OrgId, parentPath)]--
+		if j != -1 {
+			matched[i] = j
+			used[j] = true
+			newPaths[pathKey(folder.OrgId, folder.Paths)] = after[j].Paths
+		}
+	}
+
+	diff := FolderDiff{}
+	orgDiff := func(orgID uuid.UUID) *OrgDiff {
+		if diff[orgID] == nil {
+			diff[orgID] = &OrgDiff{}
+		}
+		return diff[orgID]
+	}
+	for i, folder := range before {
+		if matched[i] == -1 {
+			orgDiff(folder.OrgId).Removed = append(orgDiff(folder.OrgId).Removed, PathChange{Folder: folder, OldPath: folder.Paths})
+			continue
+		}
+		change := PathChange{Folder: after[matched[i]], OldPath: folder.Paths, NewPath: after[matched[i]].Paths}
+		switch kinds[i] {
+		case changeMoved:
+			orgDiff(folder.OrgId).Moved = append(orgDiff(folder.OrgId).Moved, change)
+		case changeRenamed:
+			orgDiff(folder.OrgId).Renamed = append(orgDiff(folder.OrgId).Renamed, change)
+		}
+	}
+	for j, folder := range after {
+		if !used[j] {
+			orgDiff(folder.OrgId).Added = append(orgDiff(folder.OrgId).Added, PathChange{Folder: folder, NewPath: folder.Paths})
+		}
+	}
+	return diff
+}
+
+// Sorted organization IDs, so the output of the writers is stable
+func (d FolderDiff) orgIDs() []uuid.UUID {
+	orgIDs := make([]uuid.UUID, 0, len(d))
+	for orgID := range d {
+		orgIDs = append(orgIDs, orgID)
+	}
+	sort.Slice(orgIDs, func(i, j int) bool {
+		return orgIDs[i].String() < orgIDs[j].String()
+	})
+	return orgIDs
+}
+
+// WriteJSON writes the diff as an indented JSON object keyed by organization ID
+func (d FolderDiff) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(d)
+}
+
+// WriteText writes the diff for a human review, one line per changed folder, e.g.
+//
+//	org c1556e17-b7c0-45a3-a6ae-9546248fb17a
+//	  added    alpha.echo
+//	  moved    alpha.bravo -> golf.bravo
+func (d FolderDiff) WriteText(w io.Writer) error {
+	_, err := io.WriteString(w, d.String())
+	return err
+}
+
+func (d FolderDiff) String() string {
+	var b strings.Builder
+	for _, orgID := range d.orgIDs() {
+		orgDiff := d[orgID]
+		fmt.Fprintf(&b, "org %s\n", orgID)
+		for _, change := range orgDiff.Added {
+			fmt.Fprintf(&b, "  added    %s\n", change.NewPath)
+		}
+		for _, change := range orgDiff.Removed {
+			fmt.Fprintf(&b, "  removed  %s\n", change.OldPath)
+		}
+		for _, change := range orgDiff.Moved {
+			fmt.Fprintf(&b, "  moved    %s -> %s\n", change.OldPath, change.NewPath)
+		}
+		for _, change := range orgDiff.Renamed {
+			fmt.Fprintf(&b, "  renamed  %s -> %s\n", change.OldPath, change.NewPath)
+		}
+	}
+	return b.String()
+}
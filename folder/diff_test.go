@@ -0,0 +1,185 @@
+package folder_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_Diff(t *testing.T) {
+	orgID := uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")
+	otherOrgID := uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")
+	before := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: orgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: orgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: orgID},
+		{Name: "delta", Paths: "alpha.delta", OrgId: orgID},
+		{Name: "echo", Paths: "echo", OrgId: orgID},
+		{Name: "golf", Paths: "golf", OrgId: otherOrgID},
+	}
+
+	testCases := []struct {
+		name  string
+		after []folder.Folder
+		want  folder.FolderDiff
+	}{
+		{
+			name:  "Same snapshot",
+			after: before,
+			want:  folder.FolderDiff{},
+		},
+		{
+			name: "Added and removed folders",
+			after: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: orgID},
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: orgID},
+				{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: orgID},
+				{Name: "delta", Paths: "alpha.delta", OrgId: orgID},
+				{Name: "golf", Paths: "golf", OrgId: otherOrgID},
+				{Name: "hotel", Paths: "golf.hotel", OrgId: otherOrgID},
+			},
+			want: folder.FolderDiff{
+				orgID: {Removed: []folder.PathChange{
+					{Folder: folder.Folder{Name: "echo", Paths: "echo", OrgId: orgID}, OldPath: "echo"},
+				}},
+				otherOrgID: {Added: []folder.PathChange{
+					{Folder: folder.Folder{Name: "hotel", Paths: "golf.hotel", OrgId: otherOrgID}, NewPath: "golf.hotel"},
+				}},
+			},
+		},
+		{
+			name: "Moved folder, its children are not listed",
+			after: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: orgID},
+				{Name: "bravo", Paths: "echo.bravo", OrgId: orgID},
+				{Name: "charlie", Paths: "echo.bravo.charlie", OrgId: orgID},
+				{Name: "delta", Paths: "alpha.delta", OrgId: orgID},
+				{Name: "echo", Paths: "echo", OrgId: orgID},
+				{Name: "golf", Paths: "golf", OrgId: otherOrgID},
+			},
+			want: folder.FolderDiff{
+				orgID: {Moved: []folder.PathChange{
+					{Folder: folder.Folder{Name: "bravo", Paths: "echo.bravo", OrgId: orgID}, OldPath: "alpha.bravo", NewPath: "echo.bravo"},
+				}},
+			},
+		},
+		{
+			name: "Renamed folder and a child moved out of it",
+			after: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: orgID},
+				{Name: "foxtrot", Paths: "alpha.foxtrot", OrgId: orgID},
+				{Name: "charlie", Paths: "echo.charlie", OrgId: orgID},
+				{Name: "delta", Paths: "alpha.delta", OrgId: orgID},
+				{Name: "echo", Paths: "echo", OrgId: orgID},
+				{Name: "golf", Paths: "golf", OrgId: otherOrgID},
+			},
+			want: folder.FolderDiff{
+				orgID: {
+					Moved: []folder.PathChange{
+						{Folder: folder.Folder{Name: "charlie", Paths: "echo.charlie", OrgId: orgID}, OldPath: "alpha.bravo.charlie", NewPath: "echo.charlie"},
+					},
+					Renamed: []folder.PathChange{
+						{Folder: folder.Folder{Name: "foxtrot", Paths: "alpha.foxtrot", OrgId: orgID}, OldPath: "alpha.bravo", NewPath: "alpha.foxtrot"},
+					},
+				},
+			},
+		},
+		{
+			name: "Folder moved and renamed at once",
+			after: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: orgID},
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: orgID},
+				{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: orgID},
+				{Name: "foxtrot", Paths: "echo.foxtrot", OrgId: orgID},
+				{Name: "echo", Paths: "echo", OrgId: orgID},
+				{Name: "golf", Paths: "golf", OrgId: otherOrgID},
+			},
+			want: folder.FolderDiff{
+				orgID: {
+					Added: []folder.PathChange{
+						{Folder: folder.Folder{Name: "foxtrot", Paths: "echo.foxtrot", OrgId: orgID}, NewPath: "echo.foxtrot"},
+					},
+					Removed: []folder.PathChange{
+						{Folder: folder.Folder{Name: "delta", Paths: "alpha.delta", OrgId: orgID}, OldPath: "alpha.delta"},
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, folder.Diff(before, test.after))
+		})
+	}
+}
+
+// The driver changes and Diff agree on what moved
+func Test_folder_Diff_Driver(t *testing.T) {
+	before := folder.GenerateDataWithSeed(1)
+	driver := folder.NewDriver(before)
+
+	// Move a second level folder of the default organization below another root folder
+	orgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	src, dst := folder.Folder{}, folder.Folder{}
+	for _, f := range before {
+		if f.OrgId == orgID && strings.Count(f.Paths, ".") == 1 && src.Name == "" {
+			src = f
+		}
+	}
+	for _, f := range before {
+		if f.OrgId == orgID && !strings.Contains(f.Paths, ".") && !strings.HasPrefix(src.Paths, f.Paths+".") {
+			dst = f
+		}
+	}
+	after, err := driver.MoveFolderByPath(src.OrgId, src.Paths, dst.Paths)
+	assert.NoError(t, err)
+
+	assert.Equal(t, folder.FolderDiff{
+		src.OrgId: {Moved: []folder.PathChange{
+			{Folder: folder.Folder{Name: src.Name, Paths: dst.Paths + "." + src.Name, OrgId: src.OrgId}, OldPath: src.Paths, NewPath: dst.Paths + "." + src.Name},
+		}},
+	}, folder.Diff(before, after))
+}
+
+func Test_folder_FolderDiff_Write(t *testing.T) {
+	orgID := uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")
+	diff := folder.FolderDiff{
+		uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b"): {Removed: []folder.PathChange{
+			{Folder: folder.Folder{Name: "golf", Paths: "golf", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")}, OldPath: "golf"},
+		}},
+		orgID: {
+			Added: []folder.PathChange{
+				{Folder: folder.Folder{Name: "echo", Paths: "alpha.echo", OrgId: orgID}, NewPath: "alpha.echo"},
+			},
+			Moved: []folder.PathChange{
+				{Folder: folder.Folder{Name: "bravo", Paths: "echo.bravo", OrgId: orgID}, OldPath: "alpha.bravo", NewPath: "echo.bravo"},
+			},
+			Renamed: []folder.PathChange{
+				{Folder: folder.Folder{Name: "foxtrot", Paths: "alpha.foxtrot", OrgId: orgID}, OldPath: "alpha.delta", NewPath: "alpha.foxtrot"},
+			},
+		},
+	}
+
+	var text bytes.Buffer
+	assert.NoError(t, diff.WriteText(&text))
+	assert.Equal(t, `org a1234567-b7c0-45a3-a6ae-9546248fb17a
+  added    alpha.echo
+  moved    alpha.bravo -> echo.bravo
+  renamed  alpha.delta -> alpha.foxtrot
+org b1234567-b7c0-45a3-a6ae-9546248fb17b
+  removed  golf
+`, text.String())
+
+	// The JSON output reads back into the same diff
+	var out bytes.Buffer
+	assert.NoError(t, diff.WriteJSON(&out))
+	decoded := folder.FolderDiff{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, diff, decoded)
+}
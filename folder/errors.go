@@ -0,0 +1,90 @@
+package folder
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofrs/uuid"
+)
+
+// Sentinel errors returned (wrapped in a *FolderError) by the driver.
+// Use errors.Is to check for them instead of comparing error strings.
+var (
+	ErrInvalidOrgID    = errors.New("invalid orgID: orgID cannot be nil")
+	ErrInvalidName     = errors.New("invalid name: folder name cannot be empty")
+	ErrInvalidLabel    = errors.New("invalid name: folder names can only contain letters, digits, underscores and hyphens, up to 1000 characters")
+	ErrInvalidDepth    = errors.New("invalid depth: depth must be at least 1")
+	ErrFolderNotFound  = errors.New("folder does not exist")
+	ErrNoParent        = errors.New("root folders have no parent")
+	ErrAmbiguousName   = errors.New("folder name is not unique in the organization, use the full path")
+	ErrMoveToSelf      = errors.New("cannot move a folder to itself")
+	ErrCrossOrg        = errors.New("cannot move a folder to a different organization")
+	ErrCycle           = errors.New("cannot move a folder to a child of itself")
+	ErrNameConflict    = errors.New("a folder with the same name already exists at the destination")
+	ErrHasChildren     = errors.New("folder has child folders")
+	ErrInvalidMode     = errors.New("invalid mode")
+	ErrInvalidPosition = errors.New("invalid position: index is out of range")
+	ErrNestedMerge     = errors.New("cannot merge a folder with one of its ancestors or children")
+)
+
+// FolderError describes a failed driver operation and the folder it failed on.
+// Err is always one of the sentinel errors above.
+type FolderError struct {
+	Op    string // operation that failed, e.g. "move"
+	Name  string
+	OrgID uuid.UUID
+	Path  string
+	Err   error
+}
+
+func (e *FolderError) Error() string {
+	msg := e.Op
+	if e.Name != "" {
+		msg += fmt.Sprintf(" '%s'", e.Name)
+	}
+	if e.Path != "" && e.Path != e.Name {
+		msg += fmt.Sprintf(" at '%s'", e.Path)
+	}
+	if e.OrgID != uuid.Nil {
+		msg += fmt.Sprintf(" in org %s", e.OrgID)
+	}
+	return msg + ": " + e.Err.Error()
+}
+
+func (e *FolderError) Unwrap() error {
+	return e.Err
+}
+
+// Helper to build a *FolderError for the given folder
+func newFolderError(op string, folder Folder, err error) error {
+	return &FolderError{Op: op, Name: folder.Name, OrgID: folder.OrgId, Path: folder.Paths, Err: err}
+}
+
+// BatchError reports the items of a batch call that failed validation, nothing was applied.
+// Errs has one entry per requested item, nil for the items that were valid.
+type BatchError struct {
+	Op   string // operation that failed, e.g. "move folders"
+	Errs []error
+}
+
+func (e *BatchError) Error() string {
+	failed := e.Unwrap()
+	msg := fmt.Sprintf("%s: %d of %d items failed", e.Op, len(failed), len(e.Errs))
+	for i, err := range e.Errs {
+		if err != nil {
+			msg += fmt.Sprintf("; #%d: %s", i, err)
+		}
+	}
+	return msg
+}
+
+// Unwrap returns the errors of the failed items, so errors.Is and errors.As look through every one of them
+func (e *BatchError) Unwrap() []error {
+	failed := []error{}
+	for _, err := range e.Errs {
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+	return failed
+}
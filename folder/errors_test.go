@@ -0,0 +1,68 @@
+package folder_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// Errors returned by the driver should carry the operation and folder details
+func Test_folder_FolderError(t *testing.T) {
+	orgID := uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: orgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: orgID},
+		{Name: "golf", Paths: "golf", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+	}
+
+	testCases := []struct {
+		name    string
+		call    func(d folder.IDriver) error
+		want    folder.FolderError
+		wantMsg string
+	}{
+		{
+			name: "Child folders of missing folder",
+			call: func(d folder.IDriver) error {
+				_, err := d.GetAllChildFolders(orgID, "missing")
+				return err
+			},
+			want:    folder.FolderError{Op: "get child folders", Name: "missing", OrgID: orgID, Err: folder.ErrFolderNotFound},
+			wantMsg: "get child folders 'missing' in org a1234567-b7c0-45a3-a6ae-9546248fb17a: folder does not exist",
+		},
+		{
+			name: "Move into own child",
+			call: func(d folder.IDriver) error {
+				_, err := d.MoveFolder(orgID, "alpha", "bravo")
+				return err
+			},
+			want:    folder.FolderError{Op: "move", Name: "alpha", OrgID: orgID, Path: "alpha", Err: folder.ErrCycle},
+			wantMsg: "move 'alpha' in org a1234567-b7c0-45a3-a6ae-9546248fb17a: cannot move a folder to a child of itself",
+		},
+		{
+			name: "Move across organizations",
+			call: func(d folder.IDriver) error {
+				_, err := d.MoveFolder(orgID, "bravo", "golf")
+				return err
+			},
+			want:    folder.FolderError{Op: "move", Name: "bravo", OrgID: orgID, Path: "alpha.bravo", Err: folder.ErrCrossOrg},
+			wantMsg: "move 'bravo' at 'alpha.bravo' in org a1234567-b7c0-45a3-a6ae-9546248fb17a: cannot move a folder to a different organization",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.call(folder.NewDriver(folders))
+
+			var folderErr *folder.FolderError
+			if assert.True(t, errors.As(err, &folderErr)) {
+				assert.Equal(t, test.want, *folderErr)
+			}
+			assert.ErrorIs(t, err, test.want.Err)
+			assert.EqualError(t, err, test.wantMsg)
+		})
+	}
+}
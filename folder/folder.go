@@ -1,34 +1,189 @@
 package folder
 
-import "github.com/gofrs/uuid"
+import (
+	"maps"
+	"sync"
 
+	"github.com/gofrs/uuid"
+)
+
+// IDriver queries and changes the folders of every organization. The methods that change folders
+// return the folder structure after the change. A driver from NewDriver only returns it and keeps
+// answering from its original folders, a stateful driver from NewStatefulDriver or NewStoreDriver keeps it.
 type IDriver interface {
 	// GetFoldersByOrgID returns all folders that belong to a specific orgID.
 	GetFoldersByOrgID(orgID uuid.UUID) []Folder
+	// GetFolderByPath returns the folder at a full ltree path within an organization.
+	GetFolderByPath(orgID uuid.UUID, path string) (Folder, error)
 	// component 1
 	// Implement the following methods:
 	// GetAllChildFolders returns all child folders of a specific folder.
+	// name can be a folder name or full path.
 	GetAllChildFolders(orgID uuid.UUID, name string) ([]Folder, error)
+	// GetChildFolders returns the child folders at most maxDepth levels below a specific folder.
+	GetChildFolders(orgID uuid.UUID, name string, maxDepth int) ([]Folder, error)
+	// GetAncestors returns the folders from the root down to the parent of a specific folder.
+	// It fails with ErrFolderNotFound when one of them is missing, the same as GetParent.
+	GetAncestors(orgID uuid.UUID, name string) ([]Folder, error)
+	// GetParent returns the direct parent of a specific folder.
+	GetParent(orgID uuid.UUID, name string) (Folder, error)
+	// GetChildrenByPath returns all child folders of the folder at a full ltree path.
+	GetChildrenByPath(orgID uuid.UUID, path string) ([]Folder, error)
 
 	// component 2
 	// Implement the following methods:
-	// MoveFolder moves a folder to a new destination.
-	MoveFolder(name string, dst string) ([]Folder, error)
+	// MoveFolder moves a folder to a new destination within the same organization.
+	// name and dst can be folder names or full paths.
+	// opts can place the folder before/after a sibling or at an index among the destination children.
+	MoveFolder(orgID uuid.UUID, name string, dst string, opts ...MoveOption) ([]Folder, error)
+	// MoveFolderByPath moves the folder at path src under the folder at path dst.
+	MoveFolderByPath(orgID uuid.UUID, src string, dst string, opts ...MoveOption) ([]Folder, error)
+	// MoveFolderToRoot moves a folder to the top level of its organization.
+	MoveFolderToRoot(orgID uuid.UUID, name string) ([]Folder, error)
+	// MoveFolders applies a batch of moves atomically, either every move is applied or none.
+	MoveFolders(orgID uuid.UUID, requests []MoveRequest) ([]Folder, error)
+
+	// CreateFolder creates a folder called name below the folder at parentPath, or a root when parentPath is empty.
+	CreateFolder(orgID uuid.UUID, parentPath string, name string) ([]Folder, error)
+	// RenameFolder renames the folder at path, the paths of its child folders follow the new name.
+	RenameFolder(orgID uuid.UUID, path string, newName string) ([]Folder, error)
+	// CopyFolder duplicates the folder at srcPath and all its children below the folder at dstPath.
+	CopyFolder(orgID uuid.UUID, srcPath string, dstPath string, opts ...CopyOption) ([]Folder, error)
+	// MergeFolders moves the children of the folder at srcPath below the folder at dstPath and removes the source.
+	MergeFolders(orgID uuid.UUID, srcPath string, dstPath string, policy CollisionPolicy) ([]Folder, []PathChange, error)
+	// DeleteFolder deletes the folder at path, mode decides what happens to its children.
+	// It returns the removed folders and the new folder structure.
+	DeleteFolder(orgID uuid.UUID, path string, mode DeleteMode) (removed []Folder, folders []Folder, err error)
+
+	// Preview dry-runs the changes made by change and returns the folder paths they would change.
+	Preview(change func(d IDriver) error) ([]PathChange, error)
 }
 
+// driver is safe for concurrent use: queries hold a read lock,
+// moves on a stateful driver hold the write lock until the new tree is committed.
 type driver struct {
+	mu        sync.RWMutex
 	folders   []Folder
-	folderMap map[string]Folder
+	folderMap map[string][]int // name+orgID -> indexes of every folder with that name
+	pathMap   map[string]int   // path+orgID -> index of the folder at that path
+	nodes     []*node          // tree index, nodes[i] is the node of folders[i]
+	stateful  bool             // whether successful changes are applied to the driver itself
+	origin    []int            // only set by Preview: index in the previewed driver of every folder, -1 for new folders
 }
 
+// NewDriver returns a driver where every call is independent:
+// MoveFolder returns the new folder structure but the driver keeps answering from the original one.
 func NewDriver(folders []Folder) IDriver {
-	folderMap := make(map[string]Folder)
-	for _, folder := range folders {
-		folderMap[folder.Name+folder.OrgId.String()] = folder
+	f := &driver{folders: folders}
+	f.reindex()
+	return f
+}
+
+// NewStatefulDriver returns a driver that applies every successful change to its own copy of folders,
+// so later queries see the result of earlier moves.
+func NewStatefulDriver(folders []Folder) IDriver {
+	f := &driver{folders: append([]Folder(nil), folders...), stateful: true}
+	f.reindex()
+	return f
+}
+
+// Rebuilds the name and path maps and the tree index from f.folders
+func (f *driver) reindex() {
+	folderMap := make(map[string][]int, len(f.folders))
+	pathMap := make(map[string]int, len(f.folders))
+	for i, folder := range f.folders {
+		key := nameKey(folder.OrgId, folder.Name)
+		folderMap[key] = append(folderMap[key], i)
+		pathMap[pathKey(folder.OrgId, folder.Paths)] = i
+	}
+
+	f.folderMap = folderMap
+	f.pathMap = pathMap
+	f.buildTree()
+}
+
+// Returns a private stateful copy of the driver, changes to the copy are validated and applied
+// without touching f until the copy is adopted. Cheaper than a reindex, the indexes are copied instead of rebuilt.
+func (f *driver) clone() *driver {
+	c := &driver{
+		folders:   append([]Folder(nil), f.folders...),
+		folderMap: maps.Clone(f.folderMap),
+		pathMap:   maps.Clone(f.pathMap),
+		nodes:     make([]*node, len(f.nodes)),
+		stateful:  true,
+	}
+	for i := range f.nodes {
+		c.nodes[i] = &node{idx: i}
+	}
+	for i, n := range f.nodes {
+		if n.parent != nil {
+			c.nodes[i].parent = c.nodes[n.parent.idx]
+		}
+		c.nodes[i].children = make([]*node, len(n.children))
+		for j, child := range n.children {
+			c.nodes[i].children[j] = c.nodes[child.idx]
+		}
+	}
+	return c
+}
+
+// Replaces the folder structure and indexes of f by those of a copy made with clone.
+// Must be called with the write lock held.
+func (f *driver) adopt(c *driver) {
+	f.folders, f.folderMap, f.pathMap, f.nodes = c.folders, c.folderMap, c.pathMap, c.nodes
+}
+
+// Appends a folder below the folder at parentIdx (-1 for a root) and adds it to every index
+func (f *driver) insert(folder Folder, parentIdx int) int {
+	f.track(keepAll, 1)
+	idx := len(f.folders)
+	f.folders = append(f.folders, folder)
+
+	key := nameKey(folder.OrgId, folder.Name)
+	f.folderMap[key] = append(f.folderMap[key], idx)
+	f.pathMap[pathKey(folder.OrgId, folder.Paths)] = idx
+
+	n := &node{idx: idx}
+	if parentIdx != -1 {
+		f.relink(n, f.nodes[parentIdx])
 	}
+	f.nodes = append(f.nodes, n)
+	return idx
+}
 
-	return &driver{
-		folders:   folders,
-		folderMap: folderMap, // Initialize the map
+// Locks the driver for a method that may change it and returns the matching unlock function.
+// Stateless drivers never change, so they only need the read lock.
+func (f *driver) lockForChange() func() {
+	if f.stateful {
+		f.mu.Lock()
+		return f.mu.Unlock
 	}
+	f.mu.RLock()
+	return f.mu.RUnlock
+}
+
+// Replaces the folder structure when the driver is stateful, otherwise it's a no-op.
+// Used by changes that add or remove many folders at once, the indexes are rebuilt from scratch.
+// Returns the folder structure to hand back to the caller, never the driver's own slice.
+// Must be called with the lock from lockForChange held.
+func (f *driver) commit(newFolders []Folder) []Folder {
+	if !f.stateful {
+		return newFolders
+	}
+
+	f.folders = newFolders
+	f.reindex()
+	return append([]Folder(nil), newFolders...)
+}
+
+// Used with track by changes that only add folders
+func keepAll(int) bool { return true }
+
+// Helpers to build the index keys used by the driver maps
+func nameKey(orgID uuid.UUID, name string) string {
+	return name + orgID.String()
+}
+
+func pathKey(orgID uuid.UUID, path string) string {
+	return path + orgID.String()
 }
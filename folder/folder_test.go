@@ -0,0 +1,182 @@
+package folder_test
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/georgechieng-sc/interns-2022/folder/sqlitestore"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// Run with `go test -race ./...` to check the driver locking.
+// Readers and writers share one stateful driver, bravo keeps moving between delta and golf.
+func Test_folder_Driver_Concurrent(t *testing.T) {
+	orgID := uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: orgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: orgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: orgID},
+		{Name: "delta", Paths: "alpha.delta", OrgId: orgID},
+		{Name: "golf", Paths: "golf", OrgId: orgID},
+	}
+	driver := folder.NewStatefulDriver(folders)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+
+		go func(i int) {
+			defer wg.Done()
+			dst := "delta"
+			if i%2 == 0 {
+				dst = "golf"
+			}
+			for j := 0; j < 50; j++ {
+				_, err := driver.MoveFolder(orgID, "bravo", dst)
+				assert.NoError(t, err)
+			}
+		}(i)
+
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				assert.Len(t, driver.GetFoldersByOrgID(orgID), len(folders))
+
+				// bravo is always somewhere, and always with charlie below it
+				children, err := driver.GetAllChildFolders(orgID, "bravo")
+				assert.NoError(t, err)
+				assert.Len(t, children, 1)
+
+				ancestors, err := driver.GetAncestors(orgID, "charlie")
+				assert.NoError(t, err)
+				assert.NotEmpty(t, ancestors)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Whatever the final location of bravo is, the indexes must agree with it
+	bravo, err := driver.GetParent(orgID, "bravo")
+	assert.NoError(t, err)
+	charlie, err := driver.GetFolderByPath(orgID, bravo.Paths+".bravo.charlie")
+	assert.NoError(t, err)
+	assert.Equal(t, "charlie", charlie.Name)
+}
+
+// Stateless drivers only take the read lock, moves must not affect concurrent readers
+func Test_folder_Driver_ConcurrentStateless(t *testing.T) {
+	orgID := uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: orgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: orgID},
+		{Name: "golf", Paths: "golf", OrgId: orgID},
+	}
+	driver := folder.NewDriver(folders)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				result, err := driver.MoveFolder(orgID, "bravo", "golf")
+				assert.NoError(t, err)
+				assert.Equal(t, "golf.bravo", result[1].Paths)
+
+				children, err := driver.GetAllChildFolders(orgID, "golf")
+				assert.NoError(t, err)
+				assert.Empty(t, children)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// A driver the table-driven suites of get_folder_test.go and move_folder_test.go run against
+type testDriver struct {
+	name      string
+	stateful  bool // whether changes are kept by the driver
+	newDriver func(folders []folder.Folder) folder.IDriver
+	// newStore is set instead of newDriver for drivers backed by a Store, it returns a store holding folders
+	newStore func(t *testing.T, folders []folder.Folder) folder.Store
+}
+
+var testDrivers = []testDriver{
+	{
+		name:      "memory",
+		newDriver: folder.NewDriver,
+	},
+	{
+		name:      "memory stateful",
+		stateful:  true,
+		newDriver: folder.NewStatefulDriver,
+	},
+	{
+		name:     "memory store",
+		stateful: true,
+		newStore: func(t *testing.T, folders []folder.Folder) folder.Store {
+			return folder.NewMemoryStore(folders)
+		},
+	},
+	{
+		name:     "sqlite store",
+		stateful: true,
+		newStore: func(t *testing.T, folders []folder.Folder) folder.Store {
+			store, err := sqlitestore.Open(filepath.Join(t.TempDir(), "folders.db"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { store.Close() })
+			if err := store.Save(folders); err != nil {
+				t.Fatal(err)
+			}
+			return store
+		},
+	},
+}
+
+// Returns a driver holding folders
+func (d testDriver) new(t *testing.T, folders []folder.Folder) folder.IDriver {
+	driver, _ := d.open(t, folders)
+	return driver
+}
+
+// Like new, but also returns reload, which loads a second driver from the store behind the first one.
+// reload is nil for drivers that aren't backed by a store.
+func (d testDriver) open(t *testing.T, folders []folder.Folder) (driver folder.IDriver, reload func() folder.IDriver) {
+	if d.newStore == nil {
+		return d.newDriver(folders), nil
+	}
+
+	store := d.newStore(t, folders)
+	reload = func() folder.IDriver {
+		driver, err := folder.NewStoreDriver(store)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return driver
+	}
+	return reload(), reload
+}
+
+// Checks that a driver reloaded from the store holds the same folders of orgID as driver,
+// so every change the driver kept was written to the store. Does nothing when reload is nil.
+func assertReloaded(t *testing.T, driver folder.IDriver, reload func() folder.IDriver, orgID uuid.UUID) {
+	t.Helper()
+	if reload == nil {
+		return
+	}
+	assert.Equal(t, driver.GetFoldersByOrgID(orgID), reload().GetFoldersByOrgID(orgID))
+}
+
+// Runs suite once for every driver of testDrivers, as a subtest named after the driver
+func forEachDriver(t *testing.T, suite func(t *testing.T, d testDriver)) {
+	for _, d := range testDrivers {
+		t.Run(d.name, func(t *testing.T) {
+			suite(t, d)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package folder
+
+import (
+	"math/rand"
+
+	"github.com/gofrs/uuid"
+	"github.com/lucasepe/codename"
+)
+
+// GeneratorConfig controls the shape of the data built by Generate.
+type GeneratorConfig struct {
+	Roots    int   // number of root folders
+	MaxChild int   // maximum children per folder, every folder above MaxDepth gets 1 to MaxChild children
+	MaxDepth int   // depth of the trees, roots are at depth 1
+	Orgs     int   // number of organizations the roots are spread across, the first one is DefaultOrgID
+	Limit    int   // stop once this many folders have been generated, 0 means no limit
+	Seed     int64 // seed for the names, org IDs and tree shape, 0 picks a random seed
+}
+
+type GeneratorOption func(*GeneratorConfig)
+
+func WithRoots(n int) GeneratorOption {
+	return func(c *GeneratorConfig) { c.Roots = n }
+}
+
+func WithMaxChild(n int) GeneratorOption {
+	return func(c *GeneratorConfig) { c.MaxChild = n }
+}
+
+func WithMaxDepth(n int) GeneratorOption {
+	return func(c *GeneratorConfig) { c.MaxDepth = n }
+}
+
+func WithOrgs(n int) GeneratorOption {
+	return func(c *GeneratorConfig) { c.Orgs = n }
+}
+
+func WithLimit(n int) GeneratorOption {
+	return func(c *GeneratorConfig) { c.Limit = n }
+}
+
+func WithSeed(seed int64) GeneratorOption {
+	return func(c *GeneratorConfig) { c.Seed = seed }
+}
+
+// Generate builds random folder trees, by default with the same limits as GenerateData.
+// The trees are built breadth first so Limit cuts off the deepest level, not whole roots.
+// Sibling names are always unique so every generated path is unique within its org.
+func Generate(opts ...GeneratorOption) []Folder {
+	config := GeneratorConfig{Roots: MaxRootSet, MaxChild: MaxChild, MaxDepth: MaxDepth, Orgs: 2}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	config.Orgs = max(config.Orgs, 1)
+	config.MaxChild = max(config.MaxChild, 1)
+
+	rng := rand.New(rand.NewSource(config.Seed))
+	if config.Seed == 0 {
+		rng, _ = codename.DefaultRNG()
+	}
+
+	orgIDs := []uuid.UUID{uuid.FromStringOrNil(DefaultOrgID)}
+	for len(orgIDs) < config.Orgs {
+		orgIDs = append(orgIDs, newOrgID(rng))
+	}
+
+	tree := []Folder{}
+	used := make(map[string]bool)
+	full := func() bool {
+		return config.Limit > 0 && len(tree) >= config.Limit
+	}
+	add := func(orgID uuid.UUID, parentPath string) Folder {
+		name, path := uniqueChild(rng, used, orgID, parentPath)
+		folder := Folder{Name: name, OrgId: orgID, Paths: path}
+		tree = append(tree, folder)
+		return folder
+	}
+
+	level := []Folder{}
+	for i := 0; i < config.Roots && !full(); i++ {
+		level = append(level, add(orgIDs[i%len(orgIDs)], ""))
+	}
+
+	for depth := 1; depth < config.MaxDepth && !full(); depth++ {
+		next := []Folder{}
+		for _, parent := range level {
+			numOfChild := rng.Intn(config.MaxChild) + 1
+			for i := 0; i < numOfChild && !full(); i++ {
+				next = append(next, add(parent.OrgId, parent.Paths))
+			}
+		}
+		level = next
+	}
+
+	return tree
+}
+
+// Picks a codename that is not used by a sibling yet, adding a random token on collisions
+func uniqueChild(rng *rand.Rand, used map[string]bool, orgID uuid.UUID, parentPath string) (string, string) {
+	name := codename.Generate(rng, 0)
+	for {
+		path := name
+		if parentPath != "" {
+			path = parentPath + "." + name
+		}
+		if key := pathKey(orgID, path); !used[key] {
+			used[key] = true
+			return name, path
+		}
+		name = codename.Generate(rng, 4)
+	}
+}
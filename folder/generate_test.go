@@ -0,0 +1,108 @@
+package folder_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_Generate(t *testing.T) {
+	testCases := []struct {
+		name      string
+		opts      []folder.GeneratorOption
+		wantLen   int // 0 skips the length check
+		wantOrgs  int
+		wantDepth int
+	}{
+		{
+			name:      "Default options",
+			opts:      nil,
+			wantOrgs:  2,
+			wantDepth: folder.MaxDepth,
+		},
+		{
+			name:      "Fixed size",
+			opts:      []folder.GeneratorOption{folder.WithRoots(10), folder.WithMaxChild(10), folder.WithMaxDepth(4), folder.WithLimit(1000)},
+			wantLen:   1000,
+			wantOrgs:  2,
+			wantDepth: 4,
+		},
+		{
+			name:      "Single level",
+			opts:      []folder.GeneratorOption{folder.WithRoots(6), folder.WithMaxDepth(1), folder.WithOrgs(3)},
+			wantLen:   6,
+			wantOrgs:  3,
+			wantDepth: 1,
+		},
+		{
+			name:      "Many roots with colliding names",
+			opts:      []folder.GeneratorOption{folder.WithRoots(3000), folder.WithMaxDepth(1), folder.WithOrgs(1), folder.WithSeed(7)},
+			wantLen:   3000,
+			wantOrgs:  1,
+			wantDepth: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			folders := folder.Generate(test.opts...)
+
+			if test.wantLen != 0 {
+				assert.Len(t, folders, test.wantLen)
+			}
+
+			orgs := map[uuid.UUID]bool{}
+			paths := map[string]bool{}
+			depth := 0
+			for _, f := range folders {
+				orgs[f.OrgId] = true
+				key := f.OrgId.String() + f.Paths
+
+				// Paths are unique and parents are generated before their children
+				assert.False(t, paths[key], "duplicate path %s", f.Paths)
+				paths[key] = true
+				if lastDot := strings.LastIndex(f.Paths, "."); lastDot != -1 {
+					assert.True(t, paths[f.OrgId.String()+f.Paths[:lastDot]], "missing parent of %s", f.Paths)
+					assert.Equal(t, f.Name, f.Paths[lastDot+1:])
+				}
+				depth = max(depth, strings.Count(f.Paths, ".")+1)
+			}
+
+			assert.Len(t, orgs, test.wantOrgs)
+			assert.True(t, orgs[uuid.FromStringOrNil(folder.DefaultOrgID)])
+			assert.LessOrEqual(t, depth, test.wantDepth)
+		})
+	}
+}
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// Seeded data is fully reproducible, including the generated org IDs
+func Test_folder_Generate_Seed(t *testing.T) {
+	opts := []folder.GeneratorOption{folder.WithOrgs(3), folder.WithSeed(42)}
+
+	assert.Equal(t, folder.Generate(opts...), folder.Generate(opts...))
+	assert.NotEqual(t, folder.Generate(opts...), folder.Generate(folder.WithOrgs(3), folder.WithSeed(43)))
+	assert.Equal(t, folder.GenerateDataWithSeed(42), folder.GenerateDataWithSeed(42))
+	assert.NotEqual(t, folder.GenerateDataWithSeed(42), folder.GenerateDataWithSeed(43))
+}
+
+// go test ./folder -run Golden -update regenerates the golden file
+func Test_folder_Generate_Golden(t *testing.T) {
+	golden := filepath.Join("testdata", "generate_seed_1.golden.json")
+	got := folder.MarshalJson(folder.Generate(folder.WithRoots(3), folder.WithMaxDepth(3), folder.WithOrgs(2), folder.WithSeed(1)))
+
+	if *update {
+		assert.NoError(t, os.WriteFile(golden, got, 0o644))
+	}
+
+	want, err := os.ReadFile(golden)
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
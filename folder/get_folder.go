@@ -1,7 +1,7 @@
 package folder
 
 import (
-	"fmt"
+	"strings"
 
 	"github.com/gofrs/uuid"
 )
@@ -11,6 +11,9 @@ func GetAllFolders() []Folder {
 }
 
 func (f *driver) GetFoldersByOrgID(orgID uuid.UUID) []Folder {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
 	folders := f.folders
 
 	res := []Folder{}
@@ -22,41 +25,196 @@ func (f *driver) GetFoldersByOrgID(orgID uuid.UUID) []Folder {
 	return res
 }
 
+// Returns the folder stored at the given path, using the path index built in NewDriver.
+func (f *driver) GetFolderByPath(orgID uuid.UUID, path string) (Folder, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	idx, err := f.lookupPath("get folder", orgID, path)
+	if err != nil {
+		return Folder{}, err
+	}
+	return f.folders[idx], nil
+}
+
 // A method to get all child folders of a given folder.
 // The method should return a list of all child folders.
 // Implement any necessary error handling (e.g. invalid orgID, invalid paths, etc).
 func (f *driver) GetAllChildFolders(orgID uuid.UUID, name string) ([]Folder, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	idx, err := f.resolveFolder("get child folders", orgID, name)
+	if err != nil {
+		return []Folder{}, err
+	}
+
+	return f.childFolders(idx, 0), nil
+}
+
+// Same as GetAllChildFolders, but only returns folders at most maxDepth levels below the given folder.
+// maxDepth 1 returns the immediate children only.
+func (f *driver) GetChildFolders(orgID uuid.UUID, name string, maxDepth int) ([]Folder, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if maxDepth < 1 {
+		return []Folder{}, &FolderError{Op: "get child folders", Name: name, OrgID: orgID, Err: ErrInvalidDepth}
+	}
+
+	idx, err := f.resolveFolder("get child folders", orgID, name)
+	if err != nil {
+		return []Folder{}, err
+	}
+
+	return f.childFolders(idx, maxDepth), nil
+}
+
+// Same as GetAllChildFolders, but the parent folder is always addressed by its full path.
+func (f *driver) GetChildrenByPath(orgID uuid.UUID, path string) ([]Folder, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	idx, err := f.lookupPath("get child folders", orgID, path)
+	if err != nil {
+		return []Folder{}, err
+	}
+
+	return f.childFolders(idx, 0), nil
+}
+
+// Returns the chain of folders from the root down to the parent of the given folder.
+// Root folders have no ancestors and return an empty list. Like GetParent, a missing folder
+// in the chain fails with ErrFolderNotFound.
+func (f *driver) GetAncestors(orgID uuid.UUID, name string) ([]Folder, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	idx, err := f.resolveFolder("get ancestors", orgID, name)
+	if err != nil {
+		return []Folder{}, err
+	}
+
+	// Every prefix of the path is an ancestor, look each one up in the path index
+	labels := strings.Split(f.folders[idx].Paths, ".")
+	ancestors := []Folder{}
+	for i := 1; i < len(labels); i++ {
+		ancestorPath := strings.Join(labels[:i], ".")
+		ancestorIdx, exists := f.pathMap[pathKey(orgID, ancestorPath)]
+		if !exists {
+			return []Folder{}, &FolderError{Op: "get ancestors", OrgID: orgID, Path: ancestorPath, Err: ErrFolderNotFound}
+		}
+		ancestors = append(ancestors, f.folders[ancestorIdx])
+	}
+	return ancestors, nil
+}
+
+// Returns the direct parent of the given folder, root folders return ErrNoParent.
+func (f *driver) GetParent(orgID uuid.UUID, name string) (Folder, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
 
+	idx, err := f.resolveFolder("get parent", orgID, name)
+	if err != nil {
+		return Folder{}, err
+	}
+
+	folder := f.folders[idx]
+	parentPath := parentOf(folder.Paths)
+	if parentPath == "" {
+		return Folder{}, newFolderError("get parent", folder, ErrNoParent)
+	}
+
+	parentIdx, exists := f.pathMap[pathKey(orgID, parentPath)]
+	if !exists {
+		return Folder{}, &FolderError{Op: "get parent", OrgID: orgID, Path: parentPath, Err: ErrFolderNotFound}
+	}
+	return f.folders[parentIdx], nil
+}
+
+// Validates the input of the name-addressed queries and finds the folder
+func (f *driver) resolveFolder(op string, orgID uuid.UUID, name string) (int, error) {
 	// Safe practice input validation
 	if orgID == uuid.Nil {
-		return []Folder{}, fmt.Errorf("invalid orgID: orgID cannot be nil")
+		return -1, &FolderError{Op: op, Name: name, Err: ErrInvalidOrgID}
 	}
 	if name == "" {
-		return []Folder{}, fmt.Errorf("invalid name: folder name cannot be empty")
+		return -1, &FolderError{Op: op, OrgID: orgID, Err: ErrInvalidName}
 	}
 
-	// Finding parent folder using the precomputed map in folder.go
-	parentKey := name + orgID.String()
-	parentFolder, exists := f.folderMap[parentKey]
+	// Finding the folder using the precomputed maps in folder.go
+	return f.resolve(op, orgID, name)
+}
+
+// Retrieve child folders of the parent, up to maxDepth levels deep (0 means no limit)
+func (f *driver) childFolders(parentIdx int, maxDepth int) []Folder {
+	childFolders := []Folder{}
+	for _, idx := range f.subtree(f.nodes[parentIdx], maxDepth) {
+		childFolders = append(childFolders, f.folders[idx])
+	}
+	return childFolders
+}
+
+// Helper function returning the depth of a path, root folders have depth 1
+func folderDepth(path string) int {
+	return strings.Count(path, ".") + 1
+}
+
+// Helper function to find the index of the folder stored at path
+func (f *driver) lookupPath(op string, orgID uuid.UUID, path string) (int, error) {
+	if orgID == uuid.Nil {
+		return -1, &FolderError{Op: op, Path: path, Err: ErrInvalidOrgID}
+	}
+	if path == "" {
+		return -1, &FolderError{Op: op, OrgID: orgID, Err: ErrInvalidName}
+	}
+
+	idx, exists := f.pathMap[pathKey(orgID, path)]
 	if !exists {
-		return []Folder{}, fmt.Errorf("folder '%s' does not exist in the specified organization", name)
+		return -1, &FolderError{Op: op, OrgID: orgID, Path: path, Err: ErrFolderNotFound}
+	}
+	return idx, nil
+}
+
+// Helper function to find the index of the folder referenced by ref within an organization.
+// An exact path match wins, otherwise ref is treated as a folder name which must be unique in the org.
+func (f *driver) resolve(op string, orgID uuid.UUID, ref string) (int, error) {
+	if idx, exists := f.pathMap[pathKey(orgID, ref)]; exists {
+		return idx, nil
 	}
 
-	// Retrieve child folders
-	var childFolders []Folder
+	var matches []int
+	if !strings.Contains(ref, ".") {
+		matches = f.folderMap[nameKey(orgID, ref)]
+	}
+
+	switch len(matches) {
+	case 0:
+		return -1, &FolderError{Op: op, Name: ref, OrgID: orgID, Err: ErrFolderNotFound}
+	case 1:
+		return matches[0], nil
+	default:
+		return -1, &FolderError{Op: op, Name: ref, OrgID: orgID, Err: ErrAmbiguousName}
+	}
+}
+
+// Helper function to check if ref names a folder that only exists outside of orgID
+func (f *driver) existsInOtherOrg(orgID uuid.UUID, ref string) bool {
 	for _, folder := range f.folders {
-		// Check if the folder's path is a child of the parent folder's path
-		if isChildFolder(folder.Paths, parentFolder.Paths) && folder.OrgId == orgID {
-			childFolders = append(childFolders, folder)
+		if folder.OrgId != orgID && (folder.Paths == ref || folder.Name == ref) {
+			return true
 		}
 	}
+	return false
+}
 
-	// Return empty folder if there are no child folders
-	if len(childFolders) == 0 {
-		return []Folder{}, nil
+// Helper function returning the path of the parent folder, or "" for a root path
+func parentOf(path string) string {
+	lastDot := strings.LastIndex(path, ".")
+	if lastDot == -1 {
+		return ""
 	}
-
-	return childFolders, nil
+	return path[:lastDot]
 }
 
 // Helper function to check if one path is a child of another
@@ -1,6 +1,7 @@
 package folder_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/georgechieng-sc/interns-2022/folder"
@@ -18,7 +19,7 @@ func Test_folder_GetAllChildFolders_InvalidNames(t *testing.T) {
 		orgID   uuid.UUID
 		folders []folder.Folder
 		want    []folder.Folder
-		errMsg  string
+		wantErr error
 	}{
 		{
 			name:  "Missing name but valid orgID",
@@ -26,26 +27,28 @@ func Test_folder_GetAllChildFolders_InvalidNames(t *testing.T) {
 			folders: []folder.Folder{
 				{Name: "", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 			},
-			want:   []folder.Folder{},
-			errMsg: "invalid name: folder name cannot be empty",
+			want:    []folder.Folder{},
+			wantErr: folder.ErrInvalidName,
 		},
 	}
 
-	for _, tests := range testCases {
-		t.Run(tests.name, func(t *testing.T) {
-			driver := folder.NewDriver(tests.folders)
-			result, error := driver.GetAllChildFolders(tests.orgID, "")
+	forEachDriver(t, func(t *testing.T, d testDriver) {
+		for _, tests := range testCases {
+			t.Run(tests.name, func(t *testing.T) {
+				driver := d.new(t, tests.folders)
+				result, error := driver.GetAllChildFolders(tests.orgID, "")
 
-			if error != nil {
-				if error.Error() != tests.errMsg {
-					t.Errorf("expected error %v, got %v", tests.errMsg, error.Error())
+				if error != nil {
+					if !errors.Is(error, tests.wantErr) {
+						t.Errorf("expected error %v, got %v", tests.wantErr, error)
+					}
+					return
 				}
-				return
-			}
 
-			assert.Equal(t, tests.want, result)
-		})
-	}
+				assert.Equal(t, tests.want, result)
+			})
+		}
+	})
 }
 
 // Invalid name input
@@ -55,7 +58,7 @@ func Test_folder_GetAllChildFolders_ExistingNames(t *testing.T) {
 		orgID   uuid.UUID
 		folders []folder.Folder
 		want    []folder.Folder
-		errMsg  string
+		wantErr error
 	}{
 		{
 			name:  "Non-existent Name with valid orgID",
@@ -64,24 +67,26 @@ func Test_folder_GetAllChildFolders_ExistingNames(t *testing.T) {
 				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 				{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 			},
-			want:   []folder.Folder{},
-			errMsg: "folder 'non_existent_folder' does not exist in the specified organization",
+			want:    []folder.Folder{},
+			wantErr: folder.ErrFolderNotFound,
 		},
 	}
 
-	for _, tests := range testsCases {
-		t.Run(tests.name, func(t *testing.T) {
-			driver := folder.NewDriver(tests.folders)
-			result, error := driver.GetAllChildFolders(tests.orgID, "non_existent_folder")
+	forEachDriver(t, func(t *testing.T, d testDriver) {
+		for _, tests := range testsCases {
+			t.Run(tests.name, func(t *testing.T) {
+				driver := d.new(t, tests.folders)
+				result, error := driver.GetAllChildFolders(tests.orgID, "non_existent_folder")
 
-			if error != nil {
-				assert.EqualError(t, error, tests.errMsg)
-				return
-			}
+				if error != nil {
+					assert.ErrorIs(t, error, tests.wantErr)
+					return
+				}
 
-			assert.Equal(t, tests.want, result)
-		})
-	}
+				assert.Equal(t, tests.want, result)
+			})
+		}
+	})
 }
 
 // Test suite where alpha is the name input for GetAllChildFolders
@@ -91,7 +96,7 @@ func Test_folder_GetAllChildFolders_Valid(t *testing.T) {
 		orgID   uuid.UUID
 		folders []folder.Folder
 		want    []folder.Folder
-		errMsg  string
+		wantErr error
 	}{
 		{
 			name:  "Missing orgID but valid name",
@@ -99,8 +104,8 @@ func Test_folder_GetAllChildFolders_Valid(t *testing.T) {
 			folders: []folder.Folder{
 				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 			},
-			want:   []folder.Folder{},
-			errMsg: "invalid orgID: orgID cannot be nil",
+			want:    []folder.Folder{},
+			wantErr: folder.ErrInvalidOrgID,
 		},
 		{
 			name:  "Valid name but orgID doesn't exist",
@@ -108,8 +113,8 @@ func Test_folder_GetAllChildFolders_Valid(t *testing.T) {
 			folders: []folder.Folder{
 				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 			},
-			want:   []folder.Folder{},
-			errMsg: "folder 'alpha' does not exist in the specified organization",
+			want:    []folder.Folder{},
+			wantErr: folder.ErrFolderNotFound,
 		},
 		{
 			name:  "No child folders",
@@ -118,8 +123,7 @@ func Test_folder_GetAllChildFolders_Valid(t *testing.T) {
 				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 				{Name: "echo", Paths: "echo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 			},
-			want:   []folder.Folder{},
-			errMsg: "",
+			want: []folder.Folder{},
 		},
 		{
 			name:  "Valid child folder - single",
@@ -131,7 +135,6 @@ func Test_folder_GetAllChildFolders_Valid(t *testing.T) {
 			want: []folder.Folder{
 				{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 			},
-			errMsg: "",
 		},
 		{
 			name:  "Valid child folders - multiple",
@@ -149,7 +152,6 @@ func Test_folder_GetAllChildFolders_Valid(t *testing.T) {
 				{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 				{Name: "delta", Paths: "alpha.delta", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 			},
-			errMsg: "",
 		},
 		{
 			name:  "Multiple folders with same name in different organizations",
@@ -162,21 +164,326 @@ func Test_folder_GetAllChildFolders_Valid(t *testing.T) {
 			want: []folder.Folder{
 				{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 			},
-			errMsg: "",
 		},
 	}
 
-	for _, tests := range testCases {
-		t.Run(tests.name, func(t *testing.T) {
-			driver := folder.NewDriver(tests.folders)
-			result, error := driver.GetAllChildFolders(tests.orgID, "alpha")
+	forEachDriver(t, func(t *testing.T, d testDriver) {
+		for _, tests := range testCases {
+			t.Run(tests.name, func(t *testing.T) {
+				driver := d.new(t, tests.folders)
+				result, error := driver.GetAllChildFolders(tests.orgID, "alpha")
+
+				if error != nil {
+					assert.ErrorIs(t, error, tests.wantErr)
+					return
+				}
+
+				assert.Equal(t, tests.want, result)
+			})
+		}
+	})
+}
+
+// Duplicate leaf names in one organization are addressed by full path
+func Test_folder_GetByPath(t *testing.T) {
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "xray", Paths: "alpha.xray", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "yankee", Paths: "alpha.xray.yankee", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "beta", Paths: "beta", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "xray", Paths: "beta.xray", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "xray", Paths: "alpha.xray", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+	}
+
+	testCases := []struct {
+		name         string
+		orgID        uuid.UUID
+		path         string
+		wantFolder   folder.Folder
+		wantChildren []folder.Folder
+		wantErr      error
+	}{
+		{
+			name:         "Duplicate name - first branch",
+			orgID:        uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			path:         "alpha.xray",
+			wantFolder:   folder.Folder{Name: "xray", Paths: "alpha.xray", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+			wantChildren: []folder.Folder{{Name: "yankee", Paths: "alpha.xray.yankee", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")}},
+		},
+		{
+			name:         "Duplicate name - second branch",
+			orgID:        uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			path:         "beta.xray",
+			wantFolder:   folder.Folder{Name: "xray", Paths: "beta.xray", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+			wantChildren: []folder.Folder{},
+		},
+		{
+			name:         "Same path in a different organization",
+			orgID:        uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b"),
+			path:         "alpha.xray",
+			wantFolder:   folder.Folder{Name: "xray", Paths: "alpha.xray", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+			wantChildren: []folder.Folder{},
+		},
+		{
+			name:    "Bare name is not a path",
+			orgID:   uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			path:    "yankee",
+			wantErr: folder.ErrFolderNotFound,
+		},
+		{
+			name:    "Missing orgID",
+			orgID:   uuid.Nil,
+			path:    "alpha",
+			wantErr: folder.ErrInvalidOrgID,
+		},
+		{
+			name:    "Empty path",
+			orgID:   uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			path:    "",
+			wantErr: folder.ErrInvalidName,
+		},
+	}
+
+	forEachDriver(t, func(t *testing.T, d testDriver) {
+		for _, tests := range testCases {
+			t.Run(tests.name, func(t *testing.T) {
+				driver := d.new(t, folders)
+
+				result, error := driver.GetFolderByPath(tests.orgID, tests.path)
+				children, childErr := driver.GetChildrenByPath(tests.orgID, tests.path)
+
+				if tests.wantErr != nil {
+					assert.ErrorIs(t, error, tests.wantErr)
+					assert.ErrorIs(t, childErr, tests.wantErr)
+					return
+				}
+
+				assert.NoError(t, error)
+				assert.NoError(t, childErr)
+				assert.Equal(t, tests.wantFolder, result)
+				assert.Equal(t, tests.wantChildren, children)
+			})
+		}
+	})
+
+	// The same folders looked up by their shared name are ambiguous
+	_, err := folder.NewDriver(folders).GetAllChildFolders(uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"), "xray")
+	assert.ErrorIs(t, err, folder.ErrAmbiguousName)
+}
+
+// Depth limited traversal, the alpha tree is three levels deep below alpha
+func Test_folder_GetChildFolders(t *testing.T) {
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "kilo", Paths: "alpha.bravo.charlie.kilo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "delta", Paths: "alpha.delta", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "echo", Paths: "echo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+	}
+
+	testCases := []struct {
+		name     string
+		orgID    uuid.UUID
+		folder   string
+		maxDepth int
+		want     []folder.Folder
+		wantErr  error
+	}{
+		{
+			name:     "Immediate children only",
+			orgID:    uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			folder:   "alpha",
+			maxDepth: 1,
+			want: []folder.Folder{
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "delta", Paths: "alpha.delta", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+			},
+		},
+		{
+			name:     "Two levels",
+			orgID:    uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			folder:   "alpha",
+			maxDepth: 2,
+			want: []folder.Folder{
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "delta", Paths: "alpha.delta", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+			},
+		},
+		{
+			name:     "Depth larger than the tree",
+			orgID:    uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			folder:   "bravo",
+			maxDepth: 10,
+			want: []folder.Folder{
+				{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "kilo", Paths: "alpha.bravo.charlie.kilo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+			},
+		},
+		{
+			name:     "Leaf folder",
+			orgID:    uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			folder:   "echo",
+			maxDepth: 1,
+			want:     []folder.Folder{},
+		},
+		{
+			name:     "Zero depth",
+			orgID:    uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			folder:   "alpha",
+			maxDepth: 0,
+			want:     []folder.Folder{},
+			wantErr:  folder.ErrInvalidDepth,
+		},
+		{
+			name:     "Non-existent folder",
+			orgID:    uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			folder:   "zulu",
+			maxDepth: 1,
+			want:     []folder.Folder{},
+			wantErr:  folder.ErrFolderNotFound,
+		},
+	}
+
+	forEachDriver(t, func(t *testing.T, d testDriver) {
+		for _, tests := range testCases {
+			t.Run(tests.name, func(t *testing.T) {
+				driver := d.new(t, folders)
+				result, error := driver.GetChildFolders(tests.orgID, tests.folder, tests.maxDepth)
 
-			if error != nil {
-				assert.EqualError(t, error, tests.errMsg)
-				return
-			}
+				if tests.wantErr != nil {
+					assert.ErrorIs(t, error, tests.wantErr)
+				} else {
+					assert.NoError(t, error)
+				}
+				assert.Equal(t, tests.want, result)
+			})
+		}
+	})
+}
 
-			assert.Equal(t, tests.want, result)
-		})
+// Ancestors are returned from the root down to the direct parent
+func Test_folder_GetAncestors(t *testing.T) {
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+		{Name: "india", Paths: "alpha.hotel.india", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 	}
+
+	testCases := []struct {
+		name          string
+		orgID         uuid.UUID
+		folder        string
+		wantAncestors []folder.Folder
+		wantParent    folder.Folder
+		wantErr       error
+		wantParentErr error
+	}{
+		{
+			name:   "Nested folder",
+			orgID:  uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			folder: "charlie",
+			wantAncestors: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+			},
+			wantParent: folder.Folder{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		},
+		{
+			name:   "Same tree in another organization",
+			orgID:  uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b"),
+			folder: "bravo",
+			wantAncestors: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+			},
+			wantParent: folder.Folder{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+		},
+		{
+			name:          "Root folder",
+			orgID:         uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			folder:        "alpha",
+			wantAncestors: []folder.Folder{},
+			wantParentErr: folder.ErrNoParent,
+		},
+		{
+			name:    "Missing intermediate folder",
+			orgID:   uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			folder:  "india",
+			wantErr: folder.ErrFolderNotFound,
+		},
+		{
+			name:    "Folder of another organization",
+			orgID:   uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b"),
+			folder:  "charlie",
+			wantErr: folder.ErrFolderNotFound,
+		},
+		{
+			name:    "Missing orgID",
+			orgID:   uuid.Nil,
+			folder:  "charlie",
+			wantErr: folder.ErrInvalidOrgID,
+		},
+	}
+
+	forEachDriver(t, func(t *testing.T, d testDriver) {
+		for _, tests := range testCases {
+			t.Run(tests.name, func(t *testing.T) {
+				driver := d.new(t, folders)
+				ancestors, error := driver.GetAncestors(tests.orgID, tests.folder)
+				parent, parentErr := driver.GetParent(tests.orgID, tests.folder)
+
+				if tests.wantErr != nil {
+					assert.ErrorIs(t, error, tests.wantErr)
+					assert.ErrorIs(t, parentErr, tests.wantErr)
+					return
+				}
+
+				assert.NoError(t, error)
+				assert.Equal(t, tests.wantAncestors, ancestors)
+				if tests.wantParentErr != nil {
+					assert.ErrorIs(t, parentErr, tests.wantParentErr)
+					return
+				}
+				assert.NoError(t, parentErr)
+				assert.Equal(t, tests.wantParent, parent)
+			})
+		}
+	})
+}
+
+// Children come back depth first, siblings ordered by Position then by slice order
+func Test_folder_GetAllChildFolders_Position(t *testing.T) {
+	orgID := uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")
+	driver := folder.NewStatefulDriver([]folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: orgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: orgID, Position: 2},
+		{Name: "charlie", Paths: "alpha.charlie", OrgId: orgID, Position: 1},
+		{Name: "kilo", Paths: "alpha.charlie.kilo", OrgId: orgID},
+		{Name: "delta", Paths: "alpha.delta", OrgId: orgID, Position: 1},
+	})
+
+	children, err := driver.GetAllChildFolders(orgID, "alpha")
+	assert.NoError(t, err)
+	assert.Equal(t, []folder.Folder{
+		{Name: "charlie", Paths: "alpha.charlie", OrgId: orgID, Position: 1},
+		{Name: "kilo", Paths: "alpha.charlie.kilo", OrgId: orgID},
+		{Name: "delta", Paths: "alpha.delta", OrgId: orgID, Position: 1},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: orgID, Position: 2},
+	}, children)
+
+	// New folders are added after the existing siblings
+	_, err = driver.CreateFolder(orgID, "alpha", "echo")
+	assert.NoError(t, err)
+	children, err = driver.GetChildFolders(orgID, "alpha", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []folder.Folder{
+		{Name: "charlie", Paths: "alpha.charlie", OrgId: orgID, Position: 1},
+		{Name: "delta", Paths: "alpha.delta", OrgId: orgID, Position: 1},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: orgID, Position: 2},
+		{Name: "echo", Paths: "alpha.echo", OrgId: orgID, Position: 2},
+	}, children)
 }
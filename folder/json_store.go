@@ -0,0 +1,59 @@
+package folder
+
+import (
+	"errors"
+	"io/fs"
+	"sync"
+)
+
+// JSONFileStore is a Store that keeps the folders in a JSON file, in the same format as sample.json.
+// Every Apply rewrites the whole file, atomically (see SaveFolders).
+type JSONFileStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewJSONFileStore returns a JSONFileStore for the file at path, the file is created by the first Save or Apply
+func NewJSONFileStore(path string) *JSONFileStore {
+	return &JSONFileStore{path: path}
+}
+
+// Load returns the folders of the file, a missing file holds no folders
+func (s *JSONFileStore) Load() ([]Folder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+func (s *JSONFileStore) Save(folders []Folder) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.save(folders)
+}
+
+func (s *JSONFileStore) Apply(changes []PathChange) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	folders, err := s.load()
+	if err != nil {
+		return err
+	}
+	newFolders, err := applyChanges(folders, changes)
+	if err != nil {
+		return err
+	}
+	return s.save(newFolders)
+}
+
+func (s *JSONFileStore) load() ([]Folder, error) {
+	folders, err := LoadFolders(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []Folder{}, nil
+	}
+	return folders, err
+}
+
+func (s *JSONFileStore) save(folders []Folder) error {
+	return SaveFolders(s.path, folders)
+}
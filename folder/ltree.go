@@ -0,0 +1,85 @@
+package folder
+
+import (
+	"strings"
+
+	"github.com/gofrs/uuid"
+)
+
+// LtreeQuery is a PostgreSQL statement with its positional parameters ($1, $2, ...).
+type LtreeQuery struct {
+	SQL  string
+	Args []any
+}
+
+// LtreeBuilder generates the PostgreSQL statements equivalent to the driver methods, for a table
+// with the columns of Folder: name, org_id, paths (an ltree column) and position.
+// The statements don't repeat the driver validation, e.g. a cycle check before a move,
+// run the change through the driver (or Preview) first.
+type LtreeBuilder struct {
+	table string
+}
+
+// NewLtreeBuilder returns a builder for the statements on table
+func NewLtreeBuilder(table string) LtreeBuilder {
+	return LtreeBuilder{table: `"` + strings.ReplaceAll(table, `"`, `""`) + `"`}
+}
+
+// ChildFolders selects every folder below path, same folders as GetChildrenByPath.
+// The rows are ordered by path, not depth first in sibling Position order like the driver returns them.
+func (b LtreeBuilder) ChildFolders(orgID uuid.UUID, path string) (LtreeQuery, error) {
+	if err := validateLtreeArgs("child folders", orgID, path); err != nil {
+		return LtreeQuery{}, err
+	}
+	return LtreeQuery{
+		SQL:  "SELECT name, org_id, paths, position FROM " + b.table + " WHERE org_id = $1 AND paths <@ $2::ltree AND paths <> $2::ltree ORDER BY paths",
+		Args: []any{orgID.String(), path},
+	}, nil
+}
+
+// Ancestors selects the folders from the root down to the parent of path, same as GetAncestors
+func (b LtreeBuilder) Ancestors(orgID uuid.UUID, path string) (LtreeQuery, error) {
+	if err := validateLtreeArgs("ancestors", orgID, path); err != nil {
+		return LtreeQuery{}, err
+	}
+	return LtreeQuery{
+		SQL:  "SELECT name, org_id, paths, position FROM " + b.table + " WHERE org_id = $1 AND paths @> $2::ltree AND paths <> $2::ltree ORDER BY nlevel(paths)",
+		Args: []any{orgID.String(), path},
+	}, nil
+}
+
+// MoveFolder rewrites the paths of the folder at srcPath and all its children in a single UPDATE,
+// same as MoveFolderByPath. An empty dstPath moves the folder to the root, same as MoveFolderToRoot.
+func (b LtreeBuilder) MoveFolder(orgID uuid.UUID, srcPath string, dstPath string) (LtreeQuery, error) {
+	if err := validateLtreeArgs("move", orgID, srcPath); err != nil {
+		return LtreeQuery{}, err
+	}
+
+	// Keep the labels from the moved folder down, e.g. moving alpha.bravo keeps bravo.charlie of alpha.bravo.charlie
+	if dstPath == "" {
+		return LtreeQuery{
+			SQL:  "UPDATE " + b.table + " SET paths = subpath(paths, nlevel($2::ltree) - 1) WHERE org_id = $1 AND paths <@ $2::ltree",
+			Args: []any{orgID.String(), srcPath},
+		}, nil
+	}
+	if err := validateLtreeArgs("move", orgID, dstPath); err != nil {
+		return LtreeQuery{}, err
+	}
+	return LtreeQuery{
+		SQL:  "UPDATE " + b.table + " SET paths = $3::ltree || subpath(paths, nlevel($2::ltree) - 1) WHERE org_id = $1 AND paths <@ $2::ltree",
+		Args: []any{orgID.String(), srcPath, dstPath},
+	}, nil
+}
+
+// Checks the arguments of a statement, every label of path must be a valid ltree label
+func validateLtreeArgs(op string, orgID uuid.UUID, path string) error {
+	if orgID == uuid.Nil {
+		return &FolderError{Op: op, Path: path, Err: ErrInvalidOrgID}
+	}
+	for _, label := range strings.Split(path, ".") {
+		if err := ValidateLabel(label); err != nil {
+			return &FolderError{Op: op, OrgID: orgID, Path: path, Err: err}
+		}
+	}
+	return nil
+}
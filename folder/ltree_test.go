@@ -0,0 +1,186 @@
+package folder_test
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_LtreeBuilder(t *testing.T) {
+	orgID := uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")
+	builder := folder.NewLtreeBuilder("folders")
+
+	testCases := []struct {
+		name    string
+		build   func() (folder.LtreeQuery, error)
+		want    folder.LtreeQuery
+		wantErr error
+	}{
+		{
+			name:  "Child folders",
+			build: func() (folder.LtreeQuery, error) { return builder.ChildFolders(orgID, "alpha.bravo") },
+			want: folder.LtreeQuery{
+				SQL:  `SELECT name, org_id, paths, position FROM "folders" WHERE org_id = $1 AND paths <@ $2::ltree AND paths <> $2::ltree ORDER BY paths`,
+				Args: []any{"a1234567-b7c0-45a3-a6ae-9546248fb17a", "alpha.bravo"},
+			},
+		},
+		{
+			name:  "Ancestors",
+			build: func() (folder.LtreeQuery, error) { return builder.Ancestors(orgID, "alpha.bravo") },
+			want: folder.LtreeQuery{
+				SQL:  `SELECT name, org_id, paths, position FROM "folders" WHERE org_id = $1 AND paths @> $2::ltree AND paths <> $2::ltree ORDER BY nlevel(paths)`,
+				Args: []any{"a1234567-b7c0-45a3-a6ae-9546248fb17a", "alpha.bravo"},
+			},
+		},
+		{
+			name:  "Move",
+			build: func() (folder.LtreeQuery, error) { return builder.MoveFolder(orgID, "alpha.bravo", "delta") },
+			want: folder.LtreeQuery{
+				SQL:  `UPDATE "folders" SET paths = $3::ltree || subpath(paths, nlevel($2::ltree) - 1) WHERE org_id = $1 AND paths <@ $2::ltree`,
+				Args: []any{"a1234567-b7c0-45a3-a6ae-9546248fb17a", "alpha.bravo", "delta"},
+			},
+		},
+		{
+			name:  "Move to the root",
+			build: func() (folder.LtreeQuery, error) { return builder.MoveFolder(orgID, "alpha.bravo", "") },
+			want: folder.LtreeQuery{
+				SQL:  `UPDATE "folders" SET paths = subpath(paths, nlevel($2::ltree) - 1) WHERE org_id = $1 AND paths <@ $2::ltree`,
+				Args: []any{"a1234567-b7c0-45a3-a6ae-9546248fb17a", "alpha.bravo"},
+			},
+		},
+		{
+			name: "Quoted table name",
+			build: func() (folder.LtreeQuery, error) {
+				return folder.NewLtreeBuilder(`my "folders"`).ChildFolders(orgID, "alpha")
+			},
+			want: folder.LtreeQuery{
+				SQL:  `SELECT name, org_id, paths, position FROM "my ""folders""" WHERE org_id = $1 AND paths <@ $2::ltree AND paths <> $2::ltree ORDER BY paths`,
+				Args: []any{"a1234567-b7c0-45a3-a6ae-9546248fb17a", "alpha"},
+			},
+		},
+		{
+			name:    "Invalid path",
+			build:   func() (folder.LtreeQuery, error) { return builder.ChildFolders(orgID, "alpha..bravo") },
+			wantErr: folder.ErrInvalidName,
+		},
+		{
+			name: "Invalid label",
+			build: func() (folder.LtreeQuery, error) {
+				return builder.MoveFolder(orgID, "alpha", "delta'; DROP TABLE folders")
+			},
+			wantErr: folder.ErrInvalidLabel,
+		},
+		{
+			name:    "Nil orgID",
+			build:   func() (folder.LtreeQuery, error) { return builder.Ancestors(uuid.Nil, "alpha") },
+			wantErr: folder.ErrInvalidOrgID,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			query, err := test.build()
+
+			assert.ErrorIs(t, err, test.wantErr)
+			assert.Equal(t, test.want, query)
+		})
+	}
+}
+
+// The ltree operators used by the generated statements, to run them against the sample data
+func ltreeDescendant(path, ancestor string) bool { // path <@ ancestor
+	return path == ancestor || strings.HasPrefix(path, ancestor+".")
+}
+
+func ltreeSubpath(path string, offset int) string {
+	return strings.Join(strings.Split(path, ".")[offset:], ".")
+}
+
+func ltreeNlevel(path string) int {
+	return strings.Count(path, ".") + 1
+}
+
+// Running the generated statements on the sample data gives the same folders as the driver
+func Test_folder_LtreeBuilder_Driver(t *testing.T) {
+	folders := folder.GenerateDataWithSeed(1)
+	orgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	driver := folder.NewDriver(folders)
+	builder := folder.NewLtreeBuilder("folders")
+
+	// A folder two levels deep, and a root folder of the same organization that is not its ancestor
+	var src, dst folder.Folder
+	for _, f := range folders {
+		if f.OrgId == orgID && ltreeNlevel(f.Paths) == 3 && src.Name == "" {
+			src = f
+		}
+	}
+	for _, f := range folders {
+		if f.OrgId == orgID && ltreeNlevel(f.Paths) == 1 && !ltreeDescendant(src.Paths, f.Paths) {
+			dst = f
+		}
+	}
+
+	t.Run("Child folders", func(t *testing.T) {
+		query, err := builder.ChildFolders(orgID, src.Paths)
+		assert.NoError(t, err)
+
+		got := []folder.Folder{}
+		for _, f := range folders {
+			if f.OrgId.String() == query.Args[0] && ltreeDescendant(f.Paths, query.Args[1].(string)) && f.Paths != query.Args[1] {
+				got = append(got, f)
+			}
+		}
+		want, err := driver.GetChildrenByPath(orgID, src.Paths)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, want)
+		// ORDER BY paths doesn't follow the driver order, only the folders are the same
+		assert.ElementsMatch(t, want, got)
+	})
+
+	t.Run("Ancestors", func(t *testing.T) {
+		query, err := builder.Ancestors(orgID, src.Paths)
+		assert.NoError(t, err)
+
+		got := []folder.Folder{}
+		for _, f := range folders {
+			if f.OrgId.String() == query.Args[0] && ltreeDescendant(query.Args[1].(string), f.Paths) && f.Paths != query.Args[1] {
+				got = append(got, f)
+			}
+		}
+		sort.Slice(got, func(i, j int) bool { return ltreeNlevel(got[i].Paths) < ltreeNlevel(got[j].Paths) })
+		want, err := driver.GetAncestors(orgID, src.Paths)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	for _, dstPath := range []string{dst.Paths, ""} {
+		t.Run("Move to '"+dstPath+"'", func(t *testing.T) {
+			query, err := builder.MoveFolder(orgID, src.Paths, dstPath)
+			assert.NoError(t, err)
+
+			got := append([]folder.Folder(nil), folders...)
+			srcPath := query.Args[1].(string)
+			for i, f := range got {
+				if f.OrgId.String() == query.Args[0] && ltreeDescendant(f.Paths, srcPath) {
+					got[i].Paths = ltreeSubpath(f.Paths, ltreeNlevel(srcPath)-1)
+					if len(query.Args) == 3 {
+						got[i].Paths = query.Args[2].(string) + "." + got[i].Paths
+					}
+				}
+			}
+
+			var want []folder.Folder
+			if dstPath == "" {
+				want, err = driver.MoveFolderToRoot(orgID, src.Paths)
+			} else {
+				want, err = driver.MoveFolderByPath(orgID, src.Paths, dstPath)
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package folder
+
+import (
+	"sync"
+)
+
+// MemoryStore is a Store that keeps the folders in memory, e.g. for tests.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	folders []Folder
+}
+
+// NewMemoryStore returns a MemoryStore holding a copy of folders
+func NewMemoryStore(folders []Folder) *MemoryStore {
+	return &MemoryStore{folders: append([]Folder(nil), folders...)}
+}
+
+func (s *MemoryStore) Load() ([]Folder, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]Folder(nil), s.folders...), nil
+}
+
+func (s *MemoryStore) Save(folders []Folder) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.folders = append([]Folder(nil), folders...)
+	return nil
+}
+
+func (s *MemoryStore) Apply(changes []PathChange) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	newFolders, err := applyChanges(s.folders, changes)
+	if err != nil {
+		return err
+	}
+	s.folders = newFolders
+	return nil
+}
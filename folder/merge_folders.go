@@ -0,0 +1,125 @@
+package folder
+
+import (
+	"fmt"
+
+	"github.com/gofrs/uuid"
+)
+
+// A method to merge the folder at srcPath into the folder at dstPath: the children of the source are moved
+// below the destination and the source, left empty, is removed. policy decides what happens when a child of
+// the source has the same name as a child of the destination:
+//   - CollisionFail: nothing is merged, the error lists every clash
+//   - CollisionSuffix: the child is moved under the first free name of the form "name-2", "name-3", ...
+//   - CollisionMerge: the two children are merged the same way, recursively
+//
+// Every clash is reported as a PathChange from the source child path to the path its content went to.
+// Returns the folder structure without the source and the clashes, with CollisionFail the clashes
+// are returned alongside the error.
+func (f *driver) MergeFolders(orgID uuid.UUID, srcPath string, dstPath string, policy CollisionPolicy) ([]Folder, []PathChange, error) {
+	defer f.lockForChange()()
+
+	srcIdx, err := f.lookupPath("merge", orgID, srcPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	sourceFolder := f.folders[srcIdx]
+
+	dstIdx, err := f.lookupPath("merge", orgID, dstPath)
+	if err != nil {
+		if f.existsInOtherOrg(orgID, dstPath) {
+			return nil, nil, newFolderError("merge", sourceFolder, ErrCrossOrg)
+		}
+		return nil, nil, err
+	}
+
+	if srcIdx == dstIdx {
+		return nil, nil, newFolderError("merge", sourceFolder, ErrMoveToSelf)
+	}
+	if isChildFolder(dstPath, srcPath) || isChildFolder(srcPath, dstPath) {
+		return nil, nil, newFolderError("merge", sourceFolder, ErrNestedMerge)
+	}
+	if policy < CollisionFail || policy > CollisionMerge {
+		return nil, nil, newFolderError("merge", sourceFolder, ErrInvalidMode)
+	}
+
+	removed := map[int]bool{}
+	newPaths := map[int]string{}
+	newNames := map[int]string{}
+	taken := map[string]bool{} // paths given to a folder by this merge
+	conflicts := []PathChange{}
+
+	// Moves the folder at idx and all its children to newPath
+	moveTo := func(idx int, newPath string) {
+		oldPath := f.folders[idx].Paths
+		for _, moved := range append([]int{idx}, f.subtree(f.nodes[idx], 0)...) {
+			newPaths[moved] = rebasePath(f.folders[moved].Paths, oldPath, newPath)
+		}
+		taken[newPath] = true
+	}
+
+	var merge func(srcIdx, dstIdx int)
+	merge = func(srcIdx, dstIdx int) {
+		removed[srcIdx] = true
+		destPath := f.folders[dstIdx].Paths
+
+		for _, child := range f.nodes[srcIdx].children {
+			childFolder := f.folders[child.idx]
+			newPath := destPath + "." + childFolder.Name
+
+			// A path given to another child by this merge clashes the same as an existing folder,
+			// only a suffixed name can take the name of a child, so CollisionMerge always has existingIdx
+			existingIdx, exists := f.pathMap[pathKey(orgID, newPath)]
+			if !exists && !taken[newPath] {
+				moveTo(child.idx, newPath)
+				continue
+			}
+
+			switch policy {
+			case CollisionFail:
+				conflicts = append(conflicts, PathChange{Folder: childFolder, OldPath: childFolder.Paths, NewPath: newPath})
+			case CollisionSuffix:
+				for n := 2; ; n++ {
+					name := fmt.Sprintf("%s-%d", childFolder.Name, n)
+					newPath = destPath + "." + name
+					if _, exists := f.pathMap[pathKey(orgID, newPath)]; !exists && !taken[newPath] {
+						newNames[child.idx] = name
+						break
+					}
+				}
+				moveTo(child.idx, newPath)
+				conflicts = append(conflicts, PathChange{Folder: childFolder, OldPath: childFolder.Paths, NewPath: newPath})
+			case CollisionMerge:
+				conflicts = append(conflicts, PathChange{Folder: childFolder, OldPath: childFolder.Paths, NewPath: newPath})
+				merge(child.idx, existingIdx)
+			}
+		}
+	}
+	merge(srcIdx, dstIdx)
+
+	if policy == CollisionFail && len(conflicts) > 0 {
+		return nil, conflicts, newFolderError("merge", sourceFolder, ErrNameConflict)
+	}
+	for idx, name := range newNames {
+		if err := ValidateLabel(name); err != nil {
+			return nil, conflicts, newFolderError("merge", f.folders[idx], err)
+		}
+	}
+
+	newFolders := make([]Folder, 0, len(f.folders)-len(removed))
+	for i, folder := range f.folders {
+		if removed[i] {
+			continue
+		}
+		if newPath, exists := newPaths[i]; exists {
+			folder.Paths = newPath
+		}
+		if newName, exists := newNames[i]; exists {
+			folder.Name = newName
+		}
+		newFolders = append(newFolders, folder)
+	}
+
+	f.track(func(idx int) bool { return !removed[idx] }, 0)
+	return f.commit(newFolders), conflicts, nil
+}
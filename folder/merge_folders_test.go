@@ -0,0 +1,236 @@
+package folder_test
+
+import (
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_MergeFolders(t *testing.T) {
+	orgID := uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: orgID},
+		{Name: "reports", Paths: "alpha.reports", OrgId: orgID},
+		{Name: "daily", Paths: "alpha.reports.daily", OrgId: orgID},
+		{Name: "mon", Paths: "alpha.reports.daily.mon", OrgId: orgID},
+		{Name: "delta", Paths: "alpha.delta", OrgId: orgID},
+		{Name: "reports", Paths: "alpha.delta.reports", OrgId: orgID},
+		{Name: "daily", Paths: "alpha.delta.reports.daily", OrgId: orgID},
+		{Name: "tue", Paths: "alpha.delta.reports.daily.tue", OrgId: orgID},
+		{Name: "weekly", Paths: "alpha.delta.reports.weekly", OrgId: orgID},
+		{Name: "echo", Paths: "echo", OrgId: orgID},
+		{Name: "golf", Paths: "golf", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+		{Name: "hotel", Paths: "hotel", OrgId: orgID},
+		{Name: "kilo", Paths: "hotel.kilo", OrgId: orgID},
+		{Name: "daily", Paths: "hotel.kilo.daily", OrgId: orgID},
+		{Name: "daily-2", Paths: "hotel.kilo.daily-2", OrgId: orgID},
+		{Name: "lima", Paths: "hotel.lima", OrgId: orgID},
+		{Name: "daily", Paths: "hotel.lima.daily", OrgId: orgID},
+	}
+
+	testCases := []struct {
+		name          string
+		src           string
+		dst           string
+		policy        folder.CollisionPolicy
+		want          []folder.Folder
+		wantConflicts []folder.PathChange
+		wantErr       error
+	}{
+		{
+			name:   "Same named children are merged",
+			src:    "alpha.delta.reports",
+			dst:    "alpha.reports",
+			policy: folder.CollisionMerge,
+			want: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: orgID},
+				{Name: "reports", Paths: "alpha.reports", OrgId: orgID},
+				{Name: "daily", Paths: "alpha.reports.daily", OrgId: orgID},
+				{Name: "mon", Paths: "alpha.reports.daily.mon", OrgId: orgID},
+				{Name: "delta", Paths: "alpha.delta", OrgId: orgID},
+				{Name: "tue", Paths: "alpha.reports.daily.tue", OrgId: orgID},
+				{Name: "weekly", Paths: "alpha.reports.weekly", OrgId: orgID},
+				{Name: "echo", Paths: "echo", OrgId: orgID},
+				{Name: "golf", Paths: "golf", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+				{Name: "hotel", Paths: "hotel", OrgId: orgID},
+				{Name: "kilo", Paths: "hotel.kilo", OrgId: orgID},
+				{Name: "daily", Paths: "hotel.kilo.daily", OrgId: orgID},
+				{Name: "daily-2", Paths: "hotel.kilo.daily-2", OrgId: orgID},
+				{Name: "lima", Paths: "hotel.lima", OrgId: orgID},
+				{Name: "daily", Paths: "hotel.lima.daily", OrgId: orgID},
+			},
+			wantConflicts: []folder.PathChange{
+				{Folder: folder.Folder{Name: "daily", Paths: "alpha.delta.reports.daily", OrgId: orgID}, OldPath: "alpha.delta.reports.daily", NewPath: "alpha.reports.daily"},
+			},
+		},
+		{
+			name:   "Same named children get a suffix",
+			src:    "alpha.delta.reports",
+			dst:    "alpha.reports",
+			policy: folder.CollisionSuffix,
+			want: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: orgID},
+				{Name: "reports", Paths: "alpha.reports", OrgId: orgID},
+				{Name: "daily", Paths: "alpha.reports.daily", OrgId: orgID},
+				{Name: "mon", Paths: "alpha.reports.daily.mon", OrgId: orgID},
+				{Name: "delta", Paths: "alpha.delta", OrgId: orgID},
+				{Name: "daily-2", Paths: "alpha.reports.daily-2", OrgId: orgID},
+				{Name: "tue", Paths: "alpha.reports.daily-2.tue", OrgId: orgID},
+				{Name: "weekly", Paths: "alpha.reports.weekly", OrgId: orgID},
+				{Name: "echo", Paths: "echo", OrgId: orgID},
+				{Name: "golf", Paths: "golf", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+				{Name: "hotel", Paths: "hotel", OrgId: orgID},
+				{Name: "kilo", Paths: "hotel.kilo", OrgId: orgID},
+				{Name: "daily", Paths: "hotel.kilo.daily", OrgId: orgID},
+				{Name: "daily-2", Paths: "hotel.kilo.daily-2", OrgId: orgID},
+				{Name: "lima", Paths: "hotel.lima", OrgId: orgID},
+				{Name: "daily", Paths: "hotel.lima.daily", OrgId: orgID},
+			},
+			wantConflicts: []folder.PathChange{
+				{Folder: folder.Folder{Name: "daily", Paths: "alpha.delta.reports.daily", OrgId: orgID}, OldPath: "alpha.delta.reports.daily", NewPath: "alpha.reports.daily-2"},
+			},
+		},
+		{
+			name:   "Suffixed name is the name of another source child",
+			src:    "hotel.kilo",
+			dst:    "hotel.lima",
+			policy: folder.CollisionSuffix,
+			want: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: orgID},
+				{Name: "reports", Paths: "alpha.reports", OrgId: orgID},
+				{Name: "daily", Paths: "alpha.reports.daily", OrgId: orgID},
+				{Name: "mon", Paths: "alpha.reports.daily.mon", OrgId: orgID},
+				{Name: "delta", Paths: "alpha.delta", OrgId: orgID},
+				{Name: "reports", Paths: "alpha.delta.reports", OrgId: orgID},
+				{Name: "daily", Paths: "alpha.delta.reports.daily", OrgId: orgID},
+				{Name: "tue", Paths: "alpha.delta.reports.daily.tue", OrgId: orgID},
+				{Name: "weekly", Paths: "alpha.delta.reports.weekly", OrgId: orgID},
+				{Name: "echo", Paths: "echo", OrgId: orgID},
+				{Name: "golf", Paths: "golf", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+				{Name: "hotel", Paths: "hotel", OrgId: orgID},
+				{Name: "daily-2", Paths: "hotel.lima.daily-2", OrgId: orgID},
+				{Name: "daily-2-2", Paths: "hotel.lima.daily-2-2", OrgId: orgID},
+				{Name: "lima", Paths: "hotel.lima", OrgId: orgID},
+				{Name: "daily", Paths: "hotel.lima.daily", OrgId: orgID},
+			},
+			wantConflicts: []folder.PathChange{
+				{Folder: folder.Folder{Name: "daily", Paths: "hotel.kilo.daily", OrgId: orgID}, OldPath: "hotel.kilo.daily", NewPath: "hotel.lima.daily-2"},
+				{Folder: folder.Folder{Name: "daily-2", Paths: "hotel.kilo.daily-2", OrgId: orgID}, OldPath: "hotel.kilo.daily-2", NewPath: "hotel.lima.daily-2-2"},
+			},
+		},
+		{
+			name:   "Same named children fail the merge",
+			src:    "alpha.delta.reports",
+			dst:    "alpha.reports",
+			policy: folder.CollisionFail,
+			wantConflicts: []folder.PathChange{
+				{Folder: folder.Folder{Name: "daily", Paths: "alpha.delta.reports.daily", OrgId: orgID}, OldPath: "alpha.delta.reports.daily", NewPath: "alpha.reports.daily"},
+			},
+			wantErr: folder.ErrNameConflict,
+		},
+		{
+			name:   "Merge without clashes",
+			src:    "alpha.delta.reports.daily",
+			dst:    "echo",
+			policy: folder.CollisionFail,
+			want: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: orgID},
+				{Name: "reports", Paths: "alpha.reports", OrgId: orgID},
+				{Name: "daily", Paths: "alpha.reports.daily", OrgId: orgID},
+				{Name: "mon", Paths: "alpha.reports.daily.mon", OrgId: orgID},
+				{Name: "delta", Paths: "alpha.delta", OrgId: orgID},
+				{Name: "reports", Paths: "alpha.delta.reports", OrgId: orgID},
+				{Name: "tue", Paths: "echo.tue", OrgId: orgID},
+				{Name: "weekly", Paths: "alpha.delta.reports.weekly", OrgId: orgID},
+				{Name: "echo", Paths: "echo", OrgId: orgID},
+				{Name: "golf", Paths: "golf", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+				{Name: "hotel", Paths: "hotel", OrgId: orgID},
+				{Name: "kilo", Paths: "hotel.kilo", OrgId: orgID},
+				{Name: "daily", Paths: "hotel.kilo.daily", OrgId: orgID},
+				{Name: "daily-2", Paths: "hotel.kilo.daily-2", OrgId: orgID},
+				{Name: "lima", Paths: "hotel.lima", OrgId: orgID},
+				{Name: "daily", Paths: "hotel.lima.daily", OrgId: orgID},
+			},
+			wantConflicts: []folder.PathChange{},
+		},
+		{
+			name:    "Merge into an ancestor",
+			src:     "alpha.delta",
+			dst:     "alpha",
+			policy:  folder.CollisionMerge,
+			wantErr: folder.ErrNestedMerge,
+		},
+		{
+			name:    "Merge into a child",
+			src:     "alpha",
+			dst:     "alpha.delta.reports",
+			policy:  folder.CollisionMerge,
+			wantErr: folder.ErrNestedMerge,
+		},
+		{
+			name:    "Merge into itself",
+			src:     "alpha.reports",
+			dst:     "alpha.reports",
+			policy:  folder.CollisionMerge,
+			wantErr: folder.ErrMoveToSelf,
+		},
+		{
+			name:    "Destination in another organization",
+			src:     "echo",
+			dst:     "golf",
+			policy:  folder.CollisionMerge,
+			wantErr: folder.ErrCrossOrg,
+		},
+		{
+			name:    "Source does not exist",
+			src:     "alpha.zulu",
+			dst:     "echo",
+			policy:  folder.CollisionMerge,
+			wantErr: folder.ErrFolderNotFound,
+		},
+		{
+			name:    "Invalid policy",
+			src:     "alpha.reports",
+			dst:     "echo",
+			policy:  folder.CollisionPolicy(42),
+			wantErr: folder.ErrInvalidMode,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			result, conflicts, err := folder.NewDriver(folders).MergeFolders(orgID, test.src, test.dst, test.policy)
+
+			assert.ErrorIs(t, err, test.wantErr)
+			assert.Equal(t, test.want, result)
+			assert.Equal(t, test.wantConflicts, conflicts)
+		})
+	}
+}
+
+// A stateful driver keeps the merged structure
+func Test_folder_MergeFolders_Stateful(t *testing.T) {
+	orgID := uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")
+	driver := folder.NewStatefulDriver([]folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: orgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: orgID},
+		{Name: "charlie", Paths: "charlie", OrgId: orgID},
+		{Name: "bravo", Paths: "charlie.bravo", OrgId: orgID},
+		{Name: "delta", Paths: "charlie.bravo.delta", OrgId: orgID},
+	})
+
+	_, _, err := driver.MergeFolders(orgID, "charlie", "alpha", folder.CollisionMerge)
+	assert.NoError(t, err)
+
+	children, err := driver.GetAllChildFolders(orgID, "alpha")
+	assert.NoError(t, err)
+	assert.Equal(t, []folder.Folder{
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: orgID},
+		{Name: "delta", Paths: "alpha.bravo.delta", OrgId: orgID},
+	}, children)
+
+	_, err = driver.GetFolderByPath(orgID, "charlie")
+	assert.ErrorIs(t, err, folder.ErrFolderNotFound)
+}
@@ -1,68 +1,275 @@
 package folder
 
 import (
-	"fmt"
+	"github.com/gofrs/uuid"
 )
 
+// MoveOption places a moved folder among the children of its destination.
+// Without options a moved folder keeps its Position.
+type MoveOption func(*moveOptions)
+
+type moveOptions struct {
+	before string
+	after  string
+	index  int
+}
+
+// MoveBefore places the moved folder just before the destination child called sibling
+func MoveBefore(sibling string) MoveOption {
+	return func(o *moveOptions) { *o = moveOptions{before: sibling, index: -1} }
+}
+
+// MoveAfter places the moved folder just after the destination child called sibling
+func MoveAfter(sibling string) MoveOption {
+	return func(o *moveOptions) { *o = moveOptions{after: sibling, index: -1} }
+}
+
+// MoveToIndex places the moved folder at index among the destination children, 0 being the first
+func MoveToIndex(index int) MoveOption {
+	return func(o *moveOptions) { *o = moveOptions{index: index} }
+}
+
 // A method to move a subtree from one parent node to another, while maintaining the order of the children.
 // The method should return the new folder structure once the move has occurred.
 // Implement any necessary error handling (e.g. invalid paths, moving a node to a child of itself, moving folders to a different orgID, etc).
-// There is no need to persist state, we can assume each method call will be independent of the previous one
-func (f *driver) MoveFolder(name string, dst string) ([]Folder, error) {
-	// Create a map for folder lookups
-	folderMap := make(map[string]*Folder)
-	for i := range f.folders {
-		folderMap[f.folders[i].Name] = &f.folders[i]
+// There is no need to persist state, we can assume each method call will be independent of the previous one,
+// unless the driver was created with NewStatefulDriver in which case the move is applied to the driver.
+//
+// name and dst are resolved within orgID: a full ltree path always identifies a single folder,
+// a bare name is only accepted when no other folder in the organization shares it.
+func (f *driver) MoveFolder(orgID uuid.UUID, name string, dst string, opts ...MoveOption) ([]Folder, error) {
+	defer f.lockForChange()()
+
+	// Safe practice input validation
+	if orgID == uuid.Nil {
+		return nil, &FolderError{Op: "move", Name: name, Err: ErrInvalidOrgID}
+	}
+	if name == "" || dst == "" {
+		return nil, &FolderError{Op: "move", Name: name, OrgID: orgID, Err: ErrInvalidName}
 	}
 
-	// Check if the source/ dest folder exists and if source = destination
-	sourceFolder, exists := folderMap[name]
-	if !exists {
-		return nil, fmt.Errorf("source folder '%s' does not exist", name)
+	// Resolve the source/ dest folder
+	srcIdx, err := f.resolve("move", orgID, name)
+	if err != nil {
+		return nil, err
 	}
 
-	destFolder, exists := folderMap[dst]
-	if !exists {
-		return nil, fmt.Errorf("destination folder '%s' does not exist", dst)
+	dstIdx, err := f.resolve("move", orgID, dst)
+	if err != nil {
+		// A destination that only exists in another organization is a cross-org move
+		if f.existsInOtherOrg(orgID, dst) {
+			return nil, newFolderError("move", f.folders[srcIdx], ErrCrossOrg)
+		}
+		return nil, err
 	}
 
-	if name == dst {
-		return nil, fmt.Errorf("cannot move a folder to itself")
+	return f.move(srcIdx, dstIdx, opts)
+}
+
+// Same as MoveFolder, but source and destination are always addressed by their full paths.
+func (f *driver) MoveFolderByPath(orgID uuid.UUID, src string, dst string, opts ...MoveOption) ([]Folder, error) {
+	defer f.lockForChange()()
+
+	srcIdx, err := f.lookupPath("move", orgID, src)
+	if err != nil {
+		return nil, err
+	}
+
+	dstIdx, err := f.lookupPath("move", orgID, dst)
+	if err != nil {
+		if f.existsInOtherOrg(orgID, dst) {
+			return nil, newFolderError("move", f.folders[srcIdx], ErrCrossOrg)
+		}
+		return nil, err
+	}
+
+	return f.move(srcIdx, dstIdx, opts)
+}
+
+// Validates and applies a move between two resolved folders, returning the new folder structure
+func (f *driver) move(srcIdx, dstIdx int, opts []MoveOption) ([]Folder, error) {
+	newPath, order, err := f.checkMove(srcIdx, dstIdx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return f.moveSubtree(srcIdx, dstIdx, newPath, order), nil
+}
+
+// Validates a move between two resolved folders without applying it,
+// returns the new path of the source folder and the new sibling order (nil when no position was requested).
+func (f *driver) checkMove(srcIdx, dstIdx int, opts []MoveOption) (string, []int, error) {
+	sourceFolder := f.folders[srcIdx]
+	destFolder := f.folders[dstIdx]
+
+	// Check if source = destination
+	if srcIdx == dstIdx {
+		return "", nil, newFolderError("move", sourceFolder, ErrMoveToSelf)
 	}
 
 	// Check if orgID for source and dest folder match
 	if sourceFolder.OrgId != destFolder.OrgId {
-		return nil, fmt.Errorf("cannot move a folder to a different organization")
+		return "", nil, newFolderError("move", sourceFolder, ErrCrossOrg)
 	}
 
 	// Check that the destination folder is not a child of the source folder
 	if isChildFolder(destFolder.Paths, sourceFolder.Paths) {
-		return nil, fmt.Errorf("cannot move a folder to a child of itself")
+		return "", nil, newFolderError("move", sourceFolder, ErrCycle)
 	}
 
-	// Create a new slice to hold the updated folder structure
-	newFolders := make([]Folder, len(f.folders))
-	copy(newFolders, f.folders) // Copy the original folder structure
-
 	// Create the new path for the source folder
 	newPath := destFolder.Paths + "." + sourceFolder.Name
 
-	// Move the source folder in the new structure
-	for i := range newFolders {
-		if newFolders[i].Name == sourceFolder.Name {
-			newFolders[i].Paths = newPath
-			break
+	// Check that the destination does not already hold a folder with the same name
+	// (moving a folder into its current parent leaves the structure unchanged)
+	if idx, exists := f.pathMap[pathKey(sourceFolder.OrgId, newPath)]; exists && idx != srcIdx {
+		return "", nil, newFolderError("move", sourceFolder, ErrNameConflict)
+	}
+
+	// Work out the new sibling order when the move asks for a position
+	order, err := f.siblingOrder(srcIdx, dstIdx, opts)
+	if err != nil {
+		return "", nil, err
+	}
+	return newPath, order, nil
+}
+
+// A method to move a folder and its children to the root of its organization,
+// the new root keeps the folder's name. name can be a folder name or a full path.
+func (f *driver) MoveFolderToRoot(orgID uuid.UUID, name string) ([]Folder, error) {
+	defer f.lockForChange()()
+
+	srcIdx, err := f.resolveFolder("move", orgID, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.checkMoveToRoot(srcIdx); err != nil {
+		return nil, err
+	}
+
+	return f.moveSubtree(srcIdx, -1, f.folders[srcIdx].Name, nil), nil
+}
+
+// Validates moving the folder at srcIdx to the root of its organization without applying it
+func (f *driver) checkMoveToRoot(srcIdx int) error {
+	sourceFolder := f.folders[srcIdx]
+
+	// Check that no other root of the organization has the same name (already a root leaves the structure unchanged)
+	if idx, exists := f.pathMap[pathKey(sourceFolder.OrgId, sourceFolder.Name)]; exists && idx != srcIdx {
+		return newFolderError("move", sourceFolder, ErrNameConflict)
+	}
+	return nil
+}
+
+// Moves the folder at srcIdx and all its children below the folder at parentIdx (-1 for the root),
+// the folder's new path is newPath. A non nil order renumbers the positions of the new siblings.
+// Returns the new folder structure, which is only kept by a stateful driver.
+func (f *driver) moveSubtree(srcIdx, parentIdx int, newPath string, order []int) []Folder {
+	if f.stateful {
+		f.relocate(srcIdx, parentIdx, newPath, order)
+
+		// Return a copy so callers can't modify the driver's state
+		return append([]Folder(nil), f.folders...)
+	}
+
+	// Move the source folder and all its children (if any) in a copy of the folder structure
+	moved := append([]int{srcIdx}, f.subtree(f.nodes[srcIdx], 0)...)
+	newFolders := rebased(f.folders, moved, f.folders[srcIdx].Paths, newPath)
+	for position, idx := range order {
+		newFolders[idx].Position = position
+	}
+	return newFolders
+}
+
+// Applies a validated move to the driver itself: relinks the source node and only rewrites the paths of the moved subtree.
+// Must be called with the lock from lockForChange held.
+func (f *driver) relocate(srcIdx, parentIdx int, newPath string, order []int) {
+	// Collect the source folder and all its children from the tree index
+	moved := append([]int{srcIdx}, f.subtree(f.nodes[srcIdx], 0)...)
+	f.rebase(moved, f.folders[srcIdx].Paths, newPath)
+
+	var parent *node
+	if parentIdx != -1 {
+		parent = f.nodes[parentIdx]
+	}
+	f.relink(f.nodes[srcIdx], parent)
+	if order != nil {
+		for position, idx := range order {
+			f.folders[idx].Position = position
+		}
+		f.sortChildren(parent)
+	}
+}
+
+// Returns the indexes of the destination children in their new order, with the source folder at the requested position.
+// Returns nil when no position was requested.
+func (f *driver) siblingOrder(srcIdx, dstIdx int, opts []MoveOption) ([]int, error) {
+	if len(opts) == 0 {
+		return nil, nil
+	}
+	options := moveOptions{index: -1}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	// Current children of the destination, without the source if it's already one of them
+	siblings := []int{}
+	for _, child := range f.nodes[dstIdx].children {
+		if child.idx != srcIdx {
+			siblings = append(siblings, child.idx)
 		}
 	}
 
-	// Move all children (if any) in the new structure
-	for i := range newFolders {
-		if isChildFolder(newFolders[i].Paths, sourceFolder.Paths) {
-			// Create the new relative path for the child folder
-			childRelativePath := newFolders[i].Paths[len(sourceFolder.Paths):]
-			// Set the new path for the child folder
-			newFolders[i].Paths = newPath + childRelativePath
+	position := options.index
+	if sibling := options.before + options.after; sibling != "" {
+		position = -1
+		for i, idx := range siblings {
+			if f.folders[idx].Name == sibling {
+				position = i
+				break
+			}
+		}
+		if position == -1 {
+			return nil, &FolderError{Op: "move", Name: sibling, OrgID: f.folders[dstIdx].OrgId, Path: f.folders[dstIdx].Paths + "." + sibling, Err: ErrFolderNotFound}
+		}
+		if options.after != "" {
+			position++
 		}
 	}
-	return newFolders, nil
+	if position < 0 || position > len(siblings) {
+		return nil, newFolderError("move", f.folders[srcIdx], ErrInvalidPosition)
+	}
+
+	order := append([]int(nil), siblings[:position]...)
+	order = append(order, srcIdx)
+	return append(order, siblings[position:]...), nil
+}
+
+// Rewrites the paths of the folders at indexes from oldPrefix to newPrefix and updates the path index.
+// Must be called with the lock from lockForChange held.
+func (f *driver) rebase(indexes []int, oldPrefix, newPrefix string) {
+	for _, idx := range indexes {
+		delete(f.pathMap, pathKey(f.folders[idx].OrgId, f.folders[idx].Paths))
+	}
+	for _, idx := range indexes {
+		f.folders[idx].Paths = rebasePath(f.folders[idx].Paths, oldPrefix, newPrefix)
+		f.pathMap[pathKey(f.folders[idx].OrgId, f.folders[idx].Paths)] = idx
+	}
+}
+
+// Same as rebase, but on a copy of folders, the original slice is left untouched
+func rebased(folders []Folder, indexes []int, oldPrefix, newPrefix string) []Folder {
+	// Create a new slice to hold the updated folder structure
+	newFolders := make([]Folder, len(folders))
+	copy(newFolders, folders) // Copy the original folder structure
+
+	for _, idx := range indexes {
+		newFolders[idx].Paths = rebasePath(newFolders[idx].Paths, oldPrefix, newPrefix)
+	}
+	return newFolders
+}
+
+// Helper function to replace the oldPrefix of a path with newPrefix,
+// the relative path below the prefix is kept as is.
+func rebasePath(path, oldPrefix, newPrefix string) string {
+	return newPrefix + path[len(oldPrefix):]
 }
@@ -1,6 +1,7 @@
 package folder_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/georgechieng-sc/interns-2022/folder"
@@ -12,66 +13,73 @@ import (
 func Test_folder_MoveFolder(t *testing.T) {
 	testCases := []struct {
 		name    string
+		orgID   uuid.UUID
 		folders []folder.Folder
 		move    string
 		dst     string
 		want    []folder.Folder
-		errMsg  string
+		wantErr error
 	}{
 		{
-			name: "Source folder does not exist",
+			name:  "Source folder does not exist",
+			orgID: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
 			folders: []folder.Folder{
 				{Name: "bravo", Paths: "bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 			},
-			move:   "nonexistent",
-			dst:    "bravo",
-			want:   nil,
-			errMsg: "source folder 'nonexistent' does not exist",
+			move:    "nonexistent",
+			dst:     "bravo",
+			want:    nil,
+			wantErr: folder.ErrFolderNotFound,
 		},
 		{
-			name: "Destination folder does not exist",
+			name:  "Destination folder does not exist",
+			orgID: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
 			folders: []folder.Folder{
 				{Name: "bravo", Paths: "bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 			},
-			move:   "bravo",
-			dst:    "nonexistent",
-			want:   nil,
-			errMsg: "destination folder 'nonexistent' does not exist",
+			move:    "bravo",
+			dst:     "nonexistent",
+			want:    nil,
+			wantErr: folder.ErrFolderNotFound,
 		},
 		{
-			name: "Move folder to a different organization",
+			name:  "Move folder to a different organization",
+			orgID: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
 			folders: []folder.Folder{
 				{Name: "bravo", Paths: "bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 				{Name: "golf", Paths: "golf", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
 			},
-			move:   "bravo",
-			dst:    "golf",
-			want:   nil,
-			errMsg: "cannot move a folder to a different organization",
+			move:    "bravo",
+			dst:     "golf",
+			want:    nil,
+			wantErr: folder.ErrCrossOrg,
 		},
 		{
-			name: "Move folder to itself",
+			name:  "Move folder to itself",
+			orgID: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
 			folders: []folder.Folder{
 				{Name: "bravo", Paths: "bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 			},
-			move:   "bravo",
-			dst:    "bravo",
-			want:   nil,
-			errMsg: "cannot move a folder to itself",
+			move:    "bravo",
+			dst:     "bravo",
+			want:    nil,
+			wantErr: folder.ErrMoveToSelf,
 		},
 		{
-			name: "Move folder to a child of itself",
+			name:  "Move folder to a child of itself",
+			orgID: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
 			folders: []folder.Folder{
 				{Name: "bravo", Paths: "bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 				{Name: "charlie", Paths: "bravo.charlie", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 			},
-			move:   "bravo",
-			dst:    "charlie",
-			want:   nil,
-			errMsg: "cannot move a folder to a child of itself",
+			move:    "bravo",
+			dst:     "charlie",
+			want:    nil,
+			wantErr: folder.ErrCycle,
 		},
 		{
-			name: "Move root folder to a new destination",
+			name:  "Move root folder to a new destination",
+			orgID: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
 			folders: []folder.Folder{
 				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 				{Name: "bravo", Paths: "bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
@@ -82,10 +90,10 @@ func Test_folder_MoveFolder(t *testing.T) {
 				{Name: "alpha", Paths: "bravo.alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 				{Name: "bravo", Paths: "bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 			},
-			errMsg: "",
 		},
 		{
-			name: "Move folder to a sibling",
+			name:  "Move folder to a sibling",
+			orgID: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
 			folders: []folder.Folder{
 				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 				{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
@@ -98,10 +106,10 @@ func Test_folder_MoveFolder(t *testing.T) {
 				{Name: "bravo", Paths: "alpha.charlie.bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 				{Name: "charlie", Paths: "alpha.charlie", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 			},
-			errMsg: "",
 		},
 		{
-			name: "valid example 1 from readme.md",
+			name:  "valid example 1 from readme.md",
+			orgID: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
 			folders: []folder.Folder{
 				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 				{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
@@ -122,10 +130,10 @@ func Test_folder_MoveFolder(t *testing.T) {
 				{Name: "foxtrot", Paths: "foxtrot", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
 				{Name: "golf", Paths: "golf", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 			},
-			errMsg: "",
 		},
 		{
-			name: "valid example 2 from readme.md",
+			name:  "valid example 2 from readme.md",
+			orgID: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
 			folders: []folder.Folder{
 				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 				{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
@@ -146,10 +154,10 @@ func Test_folder_MoveFolder(t *testing.T) {
 				{Name: "foxtrot", Paths: "foxtrot", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
 				{Name: "golf", Paths: "golf", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 			},
-			errMsg: "",
 		},
 		{
-			name: "valid example with nested folder - example 1 + beta in charlie",
+			name:  "valid example with nested folder - example 1 + beta in charlie",
+			orgID: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
 			folders: []folder.Folder{
 				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 				{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
@@ -173,20 +181,464 @@ func Test_folder_MoveFolder(t *testing.T) {
 				{Name: "golf", Paths: "golf", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
 			},
 		},
+		{
+			name:  "Same name in another organization is not moved",
+			orgID: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			folders: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "golf", Paths: "golf", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+			},
+			move: "alpha",
+			dst:  "golf",
+			want: []folder.Folder{
+				{Name: "alpha", Paths: "golf.alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "bravo", Paths: "golf.alpha.bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "golf", Paths: "golf", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+			},
+		},
+		{
+			name:  "Duplicate name within organization is ambiguous",
+			orgID: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			folders: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "xray", Paths: "alpha.xray", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "golf", Paths: "golf", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "xray", Paths: "golf.xray", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+			},
+			move:    "xray",
+			dst:     "alpha",
+			want:    nil,
+			wantErr: folder.ErrAmbiguousName,
+		},
+		{
+			name:  "Duplicate name within organization addressed by path",
+			orgID: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			folders: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "xray", Paths: "alpha.xray", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "golf", Paths: "golf", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "xray", Paths: "golf.xray", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "yankee", Paths: "golf.xray.yankee", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+			},
+			move: "golf.xray",
+			dst:  "alpha.xray",
+			want: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "xray", Paths: "alpha.xray", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "golf", Paths: "golf", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "xray", Paths: "alpha.xray.xray", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "yankee", Paths: "alpha.xray.xray.yankee", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+			},
+		},
+		{
+			name:  "Missing orgID",
+			orgID: uuid.Nil,
+			folders: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "bravo", Paths: "bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+			},
+			move:    "alpha",
+			dst:     "bravo",
+			want:    nil,
+			wantErr: folder.ErrInvalidOrgID,
+		},
+	}
+
+	forEachDriver(t, func(t *testing.T, d testDriver) {
+		for _, test := range testCases {
+			t.Run(test.name, func(t *testing.T) {
+				driver, reload := d.open(t, test.folders)
+				result, error := driver.MoveFolder(test.orgID, test.move, test.dst)
+				defer assertReloaded(t, driver, reload, test.orgID)
+
+				if test.wantErr != nil {
+					assert.ErrorIs(t, error, test.wantErr)
+					return
+				} else {
+					assert.NoError(t, error)
+				}
+				assert.ElementsMatch(t, test.want, result)
+			})
+		}
+	})
+}
+
+func Test_folder_MoveFolderByPath(t *testing.T) {
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "xray", Paths: "alpha.xray", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "beta", Paths: "beta", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "xray", Paths: "beta.xray", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "golf", Paths: "golf", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+	}
+
+	testCases := []struct {
+		name    string
+		src     string
+		dst     string
+		want    []folder.Folder
+		wantErr error
+	}{
+		{
+			name: "Move one of two folders sharing a name",
+			src:  "beta.xray",
+			dst:  "alpha.xray",
+			want: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "xray", Paths: "alpha.xray", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "beta", Paths: "beta", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "xray", Paths: "alpha.xray.xray", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "golf", Paths: "golf", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+			},
+		},
+		{
+			name:    "Destination already has a folder with the same name",
+			src:     "beta.xray",
+			dst:     "alpha",
+			wantErr: folder.ErrNameConflict,
+		},
+		{
+			name:    "Bare name is not accepted",
+			src:     "xray",
+			dst:     "alpha",
+			wantErr: folder.ErrFolderNotFound,
+		},
+		{
+			name:    "Destination in a different organization",
+			src:     "beta",
+			dst:     "golf",
+			wantErr: folder.ErrCrossOrg,
+		},
+		{
+			name:    "Move folder to a child of itself",
+			src:     "beta",
+			dst:     "beta.xray",
+			wantErr: folder.ErrCycle,
+		},
+	}
+
+	forEachDriver(t, func(t *testing.T, d testDriver) {
+		for _, test := range testCases {
+			t.Run(test.name, func(t *testing.T) {
+				driver, reload := d.open(t, folders)
+				result, error := driver.MoveFolderByPath(uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"), test.src, test.dst)
+				defer assertReloaded(t, driver, reload, uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"))
+
+				if test.wantErr != nil {
+					assert.ErrorIs(t, error, test.wantErr)
+					return
+				}
+				assert.NoError(t, error)
+				assert.ElementsMatch(t, test.want, result)
+			})
+		}
+	})
+}
+
+// A stateful driver keeps the result of every move, a stateless one does not
+func Test_folder_MoveFolder_Stateful(t *testing.T) {
+	orgID := uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: orgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: orgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: orgID},
+		{Name: "delta", Paths: "alpha.delta", OrgId: orgID},
+		{Name: "golf", Paths: "golf", OrgId: orgID},
+	}
+
+	t.Run("Moves are applied in sequence", func(t *testing.T) {
+		driver := folder.NewStatefulDriver(folders)
+
+		_, err := driver.MoveFolder(orgID, "bravo", "delta")
+		assert.NoError(t, err)
+		_, err = driver.MoveFolder(orgID, "delta", "golf")
+		assert.NoError(t, err)
+
+		children, err := driver.GetAllChildFolders(orgID, "golf")
+		assert.NoError(t, err)
+		assert.Equal(t, []folder.Folder{
+			{Name: "delta", Paths: "golf.delta", OrgId: orgID},
+			{Name: "bravo", Paths: "golf.delta.bravo", OrgId: orgID},
+			{Name: "charlie", Paths: "golf.delta.bravo.charlie", OrgId: orgID},
+		}, children)
+
+		charlie, err := driver.GetFolderByPath(orgID, "golf.delta.bravo.charlie")
+		assert.NoError(t, err)
+		assert.Equal(t, "charlie", charlie.Name)
+
+		_, err = driver.GetFolderByPath(orgID, "alpha.bravo")
+		assert.ErrorIs(t, err, folder.ErrFolderNotFound)
+
+		// delta now lives under golf, so golf can't be moved into it
+		_, err = driver.MoveFolder(orgID, "golf", "delta")
+		assert.ErrorIs(t, err, folder.ErrCycle)
+	})
+
+	t.Run("Failed moves leave the tree unchanged", func(t *testing.T) {
+		driver := folder.NewStatefulDriver(folders)
+
+		_, err := driver.MoveFolder(orgID, "alpha", "charlie")
+		assert.ErrorIs(t, err, folder.ErrCycle)
+		assert.Equal(t, folders, driver.GetFoldersByOrgID(orgID))
+	})
+
+	t.Run("Input and returned slices are not shared with the driver", func(t *testing.T) {
+		input := append([]folder.Folder(nil), folders...)
+		driver := folder.NewStatefulDriver(input)
+
+		result, err := driver.MoveFolder(orgID, "bravo", "golf")
+		assert.NoError(t, err)
+		assert.Equal(t, folders, input)
+
+		result[0].Paths = "changed"
+		alpha, err := driver.GetFolderByPath(orgID, "alpha")
+		assert.NoError(t, err)
+		assert.Equal(t, "alpha", alpha.Paths)
+	})
+
+	t.Run("Stateless driver ignores previous moves", func(t *testing.T) {
+		driver := folder.NewDriver(folders)
+
+		_, err := driver.MoveFolder(orgID, "bravo", "golf")
+		assert.NoError(t, err)
+
+		children, err := driver.GetAllChildFolders(orgID, "golf")
+		assert.NoError(t, err)
+		assert.Equal(t, []folder.Folder{}, children)
+	})
+}
+
+func Test_folder_MoveFolderToRoot(t *testing.T) {
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "echo", Paths: "alpha.echo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "echo", Paths: "echo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "bravo", Paths: "bravo", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
 	}
 
-	for _, test := range testCases {
-		t.Run(test.name, func(t *testing.T) {
-			driver := folder.NewDriver(test.folders)
-			result, error := driver.MoveFolder(test.move, test.dst)
+	testCases := []struct {
+		name    string
+		orgID   uuid.UUID
+		move    string
+		want    []folder.Folder
+		wantErr error
+	}{
+		{
+			name:  "Promote subtree to a root",
+			orgID: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			move:  "bravo",
+			want: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "bravo", Paths: "bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "charlie", Paths: "bravo.charlie", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "echo", Paths: "alpha.echo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "echo", Paths: "echo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "bravo", Paths: "bravo", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+			},
+		},
+		{
+			name:  "Already a root",
+			orgID: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			move:  "alpha",
+			want:  folders,
+		},
+		{
+			name:    "Existing root with the same name",
+			orgID:   uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			move:    "alpha.echo",
+			wantErr: folder.ErrNameConflict,
+		},
+		{
+			name:    "Folder does not exist",
+			orgID:   uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			move:    "zulu",
+			wantErr: folder.ErrFolderNotFound,
+		},
+		{
+			name:    "Missing orgID",
+			orgID:   uuid.Nil,
+			move:    "bravo",
+			wantErr: folder.ErrInvalidOrgID,
+		},
+	}
 
-			if test.errMsg != "" {
-				assert.EqualError(t, error, test.errMsg)
-				return
-			} else {
+	forEachDriver(t, func(t *testing.T, d testDriver) {
+		for _, test := range testCases {
+			t.Run(test.name, func(t *testing.T) {
+				driver, reload := d.open(t, folders)
+				result, error := driver.MoveFolderToRoot(test.orgID, test.move)
+				defer assertReloaded(t, driver, reload, test.orgID)
+				if test.wantErr != nil {
+					assert.ErrorIs(t, error, test.wantErr)
+					return
+				}
 				assert.NoError(t, error)
-			}
-			assert.ElementsMatch(t, test.want, result)
-		})
+				assert.Equal(t, test.want, result)
+
+				if !d.stateful {
+					// A stateless driver keeps answering from the original folders
+					assert.Equal(t, folder.NewDriver(folders).GetFoldersByOrgID(test.orgID), driver.GetFoldersByOrgID(test.orgID))
+					return
+				}
+
+				// A stateful driver sees the new root straight away
+				for _, f := range test.want {
+					if f.OrgId != test.orgID || strings.Contains(f.Paths, ".") {
+						continue
+					}
+					_, err := driver.GetParent(test.orgID, f.Paths)
+					assert.ErrorIs(t, err, folder.ErrNoParent)
+				}
+				children, err := driver.GetAllChildFolders(test.orgID, "alpha")
+				assert.NoError(t, err)
+				for _, child := range children {
+					assert.True(t, strings.HasPrefix(child.Paths, "alpha."))
+				}
+			})
+		}
+	})
+}
+
+// Position aware moves renumber the destination children, GetAllChildFolders follows the new order
+func Test_folder_MoveFolder_Position(t *testing.T) {
+	orgID := uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: orgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: orgID},
+		{Name: "charlie", Paths: "alpha.charlie", OrgId: orgID},
+		{Name: "delta", Paths: "alpha.delta", OrgId: orgID},
+		{Name: "golf", Paths: "golf", OrgId: orgID},
+		{Name: "hotel", Paths: "golf.hotel", OrgId: orgID},
+		{Name: "india", Paths: "golf.hotel.india", OrgId: orgID},
+	}
+
+	testCases := []struct {
+		name         string
+		move         string
+		dst          string
+		opts         []folder.MoveOption
+		wantChildren []folder.Folder // children of dst after the move
+		wantErr      error
+	}{
+		{
+			name: "Before a sibling",
+			move: "hotel",
+			dst:  "alpha",
+			opts: []folder.MoveOption{folder.MoveBefore("charlie")},
+			wantChildren: []folder.Folder{
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: orgID, Position: 0},
+				{Name: "hotel", Paths: "alpha.hotel", OrgId: orgID, Position: 1},
+				{Name: "india", Paths: "alpha.hotel.india", OrgId: orgID},
+				{Name: "charlie", Paths: "alpha.charlie", OrgId: orgID, Position: 2},
+				{Name: "delta", Paths: "alpha.delta", OrgId: orgID, Position: 3},
+			},
+		},
+		{
+			name: "After a sibling",
+			move: "hotel",
+			dst:  "alpha",
+			opts: []folder.MoveOption{folder.MoveAfter("delta")},
+			wantChildren: []folder.Folder{
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: orgID, Position: 0},
+				{Name: "charlie", Paths: "alpha.charlie", OrgId: orgID, Position: 1},
+				{Name: "delta", Paths: "alpha.delta", OrgId: orgID, Position: 2},
+				{Name: "hotel", Paths: "alpha.hotel", OrgId: orgID, Position: 3},
+				{Name: "india", Paths: "alpha.hotel.india", OrgId: orgID},
+			},
+		},
+		{
+			name: "First child",
+			move: "hotel",
+			dst:  "alpha",
+			opts: []folder.MoveOption{folder.MoveToIndex(0)},
+			wantChildren: []folder.Folder{
+				{Name: "hotel", Paths: "alpha.hotel", OrgId: orgID, Position: 0},
+				{Name: "india", Paths: "alpha.hotel.india", OrgId: orgID},
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: orgID, Position: 1},
+				{Name: "charlie", Paths: "alpha.charlie", OrgId: orgID, Position: 2},
+				{Name: "delta", Paths: "alpha.delta", OrgId: orgID, Position: 3},
+			},
+		},
+		{
+			name: "Reorder within the same parent",
+			move: "delta",
+			dst:  "alpha",
+			opts: []folder.MoveOption{folder.MoveBefore("bravo")},
+			wantChildren: []folder.Folder{
+				{Name: "delta", Paths: "alpha.delta", OrgId: orgID, Position: 0},
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: orgID, Position: 1},
+				{Name: "charlie", Paths: "alpha.charlie", OrgId: orgID, Position: 2},
+			},
+		},
+		{
+			name: "Without options the folder keeps its position",
+			move: "hotel",
+			dst:  "alpha",
+			wantChildren: []folder.Folder{
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: orgID},
+				{Name: "charlie", Paths: "alpha.charlie", OrgId: orgID},
+				{Name: "delta", Paths: "alpha.delta", OrgId: orgID},
+				{Name: "hotel", Paths: "alpha.hotel", OrgId: orgID},
+				{Name: "india", Paths: "alpha.hotel.india", OrgId: orgID},
+			},
+		},
+		{
+			name:    "Sibling does not exist",
+			move:    "hotel",
+			dst:     "alpha",
+			opts:    []folder.MoveOption{folder.MoveAfter("zulu")},
+			wantErr: folder.ErrFolderNotFound,
+		},
+		{
+			name:    "Index out of range",
+			move:    "hotel",
+			dst:     "alpha",
+			opts:    []folder.MoveOption{folder.MoveToIndex(4)},
+			wantErr: folder.ErrInvalidPosition,
+		},
+		{
+			name:    "Negative index",
+			move:    "hotel",
+			dst:     "alpha",
+			opts:    []folder.MoveOption{folder.MoveToIndex(-1)},
+			wantErr: folder.ErrInvalidPosition,
+		},
 	}
+
+	forEachDriver(t, func(t *testing.T, d testDriver) {
+		// Only stateful drivers keep the new positions
+		if !d.stateful {
+			return
+		}
+		for _, test := range testCases {
+			t.Run(test.name, func(t *testing.T) {
+				driver, reload := d.open(t, folders)
+				result, error := driver.MoveFolder(orgID, test.move, test.dst, test.opts...)
+				defer assertReloaded(t, driver, reload, orgID)
+
+				if test.wantErr != nil {
+					assert.ErrorIs(t, error, test.wantErr)
+					assert.Equal(t, folders, driver.GetFoldersByOrgID(orgID))
+					return
+				}
+				assert.NoError(t, error)
+
+				children, err := driver.GetAllChildFolders(orgID, test.dst)
+				assert.NoError(t, err)
+				assert.Equal(t, test.wantChildren, children)
+
+				// The stateless result carries the same positions
+				stateless, err := folder.NewDriver(folders).MoveFolder(orgID, test.move, test.dst, test.opts...)
+				assert.NoError(t, err)
+				assert.Equal(t, result, stateless)
+			})
+		}
+	})
 }
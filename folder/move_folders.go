@@ -0,0 +1,85 @@
+package folder
+
+import (
+	"github.com/gofrs/uuid"
+)
+
+// MoveRequest is a single move of a MoveFolders batch.
+// Name and Dst are resolved like in MoveFolder, an empty Dst is rejected the same way.
+type MoveRequest struct {
+	Name    string
+	Dst     string
+	Options []MoveOption
+	// ToRoot moves the folder to the root of its organization like MoveFolderToRoot, Dst must be empty
+	ToRoot bool
+}
+
+// A method to apply many moves in a single call, either all of them are applied or none.
+// The moves are validated in order against the tree left by the previous ones, so a move can
+// depend on an earlier move of the same batch (e.g. move a folder, then move something into its new location).
+// When any move is invalid the driver is left unchanged and a *BatchError reports every failed move,
+// the moves after a failed one are validated as if the failed one was not in the batch.
+// Returns the folder structure left by the whole batch.
+func (f *driver) MoveFolders(orgID uuid.UUID, requests []MoveRequest) ([]Folder, error) {
+	defer f.lockForChange()()
+
+	if orgID == uuid.Nil {
+		return nil, &FolderError{Op: "move folders", Err: ErrInvalidOrgID}
+	}
+
+	// Apply the moves to a private stateful copy, so each move only updates the moved subtree
+	// and the driver is untouched until every move is known to be valid
+	scratch := f.clone()
+
+	errs := make([]error, len(requests))
+	failed := false
+	for i, request := range requests {
+		if errs[i] = scratch.applyMove(orgID, request); errs[i] != nil {
+			failed = true
+		}
+	}
+	if failed {
+		return nil, &BatchError{Op: "move folders", Errs: errs}
+	}
+
+	if !f.stateful {
+		return scratch.folders, nil
+	}
+	f.adopt(scratch)
+	return append([]Folder(nil), f.folders...), nil
+}
+
+// Validates a single move of a batch and applies it to the driver, which must be stateful
+func (f *driver) applyMove(orgID uuid.UUID, request MoveRequest) error {
+	if request.Name == "" || (request.Dst == "") != request.ToRoot {
+		return &FolderError{Op: "move", Name: request.Name, OrgID: orgID, Err: ErrInvalidName}
+	}
+
+	srcIdx, err := f.resolve("move", orgID, request.Name)
+	if err != nil {
+		return err
+	}
+
+	if request.ToRoot {
+		if err := f.checkMoveToRoot(srcIdx); err != nil {
+			return err
+		}
+		f.relocate(srcIdx, -1, f.folders[srcIdx].Name, nil)
+		return nil
+	}
+
+	dstIdx, err := f.resolve("move", orgID, request.Dst)
+	if err != nil {
+		if f.existsInOtherOrg(orgID, request.Dst) {
+			return newFolderError("move", f.folders[srcIdx], ErrCrossOrg)
+		}
+		return err
+	}
+
+	newPath, order, err := f.checkMove(srcIdx, dstIdx, request.Options)
+	if err != nil {
+		return err
+	}
+	f.relocate(srcIdx, dstIdx, newPath, order)
+	return nil
+}
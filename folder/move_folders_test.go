@@ -0,0 +1,157 @@
+package folder_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_MoveFolders(t *testing.T) {
+	orgID := uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: orgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: orgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: orgID},
+		{Name: "delta", Paths: "alpha.delta", OrgId: orgID},
+		{Name: "echo", Paths: "echo", OrgId: orgID},
+		{Name: "golf", Paths: "golf", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+	}
+
+	testCases := []struct {
+		name     string
+		requests []folder.MoveRequest
+		want     []folder.Folder
+		wantErrs []error // one entry per request, nil for the valid ones
+	}{
+		{
+			name: "Moves that depend on earlier moves",
+			requests: []folder.MoveRequest{
+				{Name: "bravo", Dst: "echo"},
+				{Name: "delta", Dst: "echo.bravo"},
+				{Name: "echo.bravo.charlie", Dst: "delta"},
+			},
+			want: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: orgID},
+				{Name: "bravo", Paths: "echo.bravo", OrgId: orgID},
+				{Name: "charlie", Paths: "echo.bravo.delta.charlie", OrgId: orgID},
+				{Name: "delta", Paths: "echo.bravo.delta", OrgId: orgID},
+				{Name: "echo", Paths: "echo", OrgId: orgID},
+				{Name: "golf", Paths: "golf", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+			},
+		},
+		{
+			name: "Move to the root and position",
+			requests: []folder.MoveRequest{
+				{Name: "bravo", ToRoot: true},
+				{Name: "echo", Dst: "alpha", Options: []folder.MoveOption{folder.MoveToIndex(0)}},
+			},
+			want: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: orgID},
+				{Name: "bravo", Paths: "bravo", OrgId: orgID},
+				{Name: "charlie", Paths: "bravo.charlie", OrgId: orgID},
+				{Name: "delta", Paths: "alpha.delta", OrgId: orgID, Position: 1},
+				{Name: "echo", Paths: "alpha.echo", OrgId: orgID},
+				{Name: "golf", Paths: "golf", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+			},
+		},
+		{
+			name:     "Empty batch",
+			requests: []folder.MoveRequest{},
+			want:     folders,
+		},
+		{
+			name: "Every failed move is reported",
+			requests: []folder.MoveRequest{
+				{Name: "bravo", Dst: "echo"},
+				{Name: "alpha", Dst: "golf"},
+				{Name: "echo", Dst: "charlie"}, // charlie has moved below echo
+				{Name: "zulu", Dst: "alpha"},
+				{Name: "delta", Dst: "echo"},
+			},
+			wantErrs: []error{nil, folder.ErrCrossOrg, folder.ErrCycle, folder.ErrFolderNotFound, nil},
+		},
+		{
+			name: "Missing destination is not a move to the root",
+			requests: []folder.MoveRequest{
+				{Name: "bravo"},
+				{Name: "delta", Dst: "echo", ToRoot: true},
+				{Name: "echo", ToRoot: true},
+			},
+			wantErrs: []error{folder.ErrInvalidName, folder.ErrInvalidName, nil},
+		},
+		{
+			name: "Moves after a failed move ignore it",
+			requests: []folder.MoveRequest{
+				{Name: "bravo", Dst: "bravo"},
+				{Name: "alpha.bravo", Dst: "echo"},
+				{Name: "echo.bravo.charlie", Dst: "alpha"},
+				{Name: "delta", Dst: "charlie"},
+				{Name: "alpha.charlie", Dst: "zulu"},
+			},
+			wantErrs: []error{folder.ErrMoveToSelf, nil, nil, nil, folder.ErrFolderNotFound},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			for _, driver := range []folder.IDriver{folder.NewDriver(folders), folder.NewStatefulDriver(folders)} {
+				result, err := driver.MoveFolders(orgID, test.requests)
+
+				if test.wantErrs != nil {
+					var batchErr *folder.BatchError
+					if assert.True(t, errors.As(err, &batchErr)) && assert.Len(t, batchErr.Errs, len(test.wantErrs)) {
+						for i, wantErr := range test.wantErrs {
+							if wantErr == nil {
+								assert.NoError(t, batchErr.Errs[i])
+							} else {
+								assert.ErrorIs(t, batchErr.Errs[i], wantErr)
+							}
+						}
+					}
+					assert.Nil(t, result)
+					// Nothing was applied
+					assert.Equal(t, folders[:5], driver.GetFoldersByOrgID(orgID))
+					continue
+				}
+
+				assert.NoError(t, err)
+				assert.Equal(t, test.want, result)
+			}
+		})
+	}
+}
+
+// A stateful driver keeps the result of the batch
+func Test_folder_MoveFolders_Stateful(t *testing.T) {
+	orgID := uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")
+	driver := folder.NewStatefulDriver([]folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: orgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: orgID},
+		{Name: "charlie", Paths: "charlie", OrgId: orgID},
+	})
+
+	_, err := driver.MoveFolders(orgID, []folder.MoveRequest{
+		{Name: "bravo", Dst: "charlie"},
+		{Name: "alpha", Dst: "charlie.bravo"},
+	})
+	assert.NoError(t, err)
+
+	children, err := driver.GetAllChildFolders(orgID, "charlie")
+	assert.NoError(t, err)
+	assert.Equal(t, []folder.Folder{
+		{Name: "bravo", Paths: "charlie.bravo", OrgId: orgID},
+		{Name: "alpha", Paths: "charlie.bravo.alpha", OrgId: orgID},
+	}, children)
+
+	// The batch error lists the failed moves
+	_, err = driver.MoveFolders(orgID, []folder.MoveRequest{
+		{Name: "charlie", Dst: "alpha"},
+		{Name: "", Dst: "charlie"},
+	})
+	assert.EqualError(t, err, "move folders: 2 of 2 items failed; "+
+		"#0: move 'charlie' in org a1234567-b7c0-45a3-a6ae-9546248fb17a: cannot move a folder to a child of itself; "+
+		"#1: move in org a1234567-b7c0-45a3-a6ae-9546248fb17a: invalid name: folder name cannot be empty")
+}
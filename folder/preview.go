@@ -0,0 +1,90 @@
+package folder
+
+// PathChange is a folder whose path is changed by a previewed change.
+// OldPath is empty for a created folder, NewPath is empty for a removed one.
+type PathChange struct {
+	Folder  Folder `json:"folder"` // the folder after the change, or as it was for a removed folder
+	OldPath string `json:"oldPath,omitempty"`
+	NewPath string `json:"newPath,omitempty"`
+}
+
+// A method to dry-run one or more changes: change is called with a private stateful copy of the driver
+// and can call any mutating method on it, later calls see the result of earlier ones.
+// The driver itself is never changed. Returns the error of change, or the path of every folder that
+// the change would create, move, rename or remove. The copy goes through the same validation as the real
+// methods, so a successful preview is guaranteed to apply as long as the driver doesn't change in between.
+func (f *driver) Preview(change func(d IDriver) error) ([]PathChange, error) {
+	before, preview, err := f.dryRun(change)
+	if err != nil {
+		return nil, err
+	}
+	return pathChanges(before, preview, false), nil
+}
+
+// Runs change on a private stateful copy of the driver, returns the folders before the change and the copy.
+func (f *driver) dryRun(change func(d IDriver) error) ([]Folder, *driver, error) {
+	f.mu.RLock()
+	before := append([]Folder(nil), f.folders...)
+	preview := f.clone()
+	f.mu.RUnlock()
+
+	preview.origin = make([]int, len(before))
+	for i := range preview.origin {
+		preview.origin[i] = i
+	}
+
+	if err := change(preview); err != nil {
+		return nil, nil, err
+	}
+	return before, preview, nil
+}
+
+// Lists the folders created, removed or with a new path in preview compared to before.
+// With positions, folders that only have a new Position are listed too, with the same old and new path.
+func pathChanges(before []Folder, preview *driver, positions bool) []PathChange {
+	// Match every folder of the preview with the folder it comes from
+	after := make([]int, len(before))
+	for i := range after {
+		after[i] = -1
+	}
+	created := []PathChange{}
+	for i, origin := range preview.origin {
+		if origin == -1 {
+			created = append(created, PathChange{Folder: preview.folders[i], NewPath: preview.folders[i].Paths})
+			continue
+		}
+		after[origin] = i
+	}
+
+	changes := []PathChange{}
+	for i, folder := range before {
+		if after[i] == -1 {
+			changes = append(changes, PathChange{Folder: folder, OldPath: folder.Paths})
+			continue
+		}
+		newFolder := preview.folders[after[i]]
+		if newFolder.Paths != folder.Paths || positions && newFolder.Position != folder.Position {
+			changes = append(changes, PathChange{Folder: newFolder, OldPath: folder.Paths, NewPath: newFolder.Paths})
+		}
+	}
+	return append(changes, created...)
+}
+
+// Keeps the origin of a preview copy in step with a change that replaces f.folders by the folders
+// for which kept returns true, followed by added new folders. A no-op outside of Preview.
+func (f *driver) track(kept func(idx int) bool, added int) {
+	if f.origin == nil {
+		return
+	}
+
+	origin := make([]int, 0, len(f.origin)+added)
+	for idx, o := range f.origin {
+		if kept(idx) {
+			origin = append(origin, o)
+		}
+	}
+	for i := 0; i < added; i++ {
+		origin = append(origin, -1)
+	}
+	f.origin = origin
+}
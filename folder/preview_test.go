@@ -0,0 +1,113 @@
+package folder_test
+
+import (
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_Preview(t *testing.T) {
+	orgID := uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: orgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: orgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: orgID},
+		{Name: "delta", Paths: "alpha.delta", OrgId: orgID},
+		{Name: "echo", Paths: "echo", OrgId: orgID},
+	}
+
+	testCases := []struct {
+		name    string
+		change  func(d folder.IDriver) error
+		want    []folder.PathChange
+		wantErr error
+	}{
+		{
+			name: "Move a folder and its children",
+			change: func(d folder.IDriver) error {
+				_, err := d.MoveFolder(orgID, "bravo", "echo")
+				return err
+			},
+			want: []folder.PathChange{
+				{Folder: folder.Folder{Name: "bravo", Paths: "echo.bravo", OrgId: orgID}, OldPath: "alpha.bravo", NewPath: "echo.bravo"},
+				{Folder: folder.Folder{Name: "charlie", Paths: "echo.bravo.charlie", OrgId: orgID}, OldPath: "alpha.bravo.charlie", NewPath: "echo.bravo.charlie"},
+			},
+		},
+		{
+			name: "Rename",
+			change: func(d folder.IDriver) error {
+				_, err := d.RenameFolder(orgID, "alpha.bravo", "foxtrot")
+				return err
+			},
+			want: []folder.PathChange{
+				{Folder: folder.Folder{Name: "foxtrot", Paths: "alpha.foxtrot", OrgId: orgID}, OldPath: "alpha.bravo", NewPath: "alpha.foxtrot"},
+				{Folder: folder.Folder{Name: "charlie", Paths: "alpha.foxtrot.charlie", OrgId: orgID}, OldPath: "alpha.bravo.charlie", NewPath: "alpha.foxtrot.charlie"},
+			},
+		},
+		{
+			name: "Delete and reparent",
+			change: func(d folder.IDriver) error {
+				_, _, err := d.DeleteFolder(orgID, "alpha.bravo", folder.DeleteReparent)
+				return err
+			},
+			want: []folder.PathChange{
+				{Folder: folder.Folder{Name: "bravo", Paths: "alpha.bravo", OrgId: orgID}, OldPath: "alpha.bravo"},
+				{Folder: folder.Folder{Name: "charlie", Paths: "alpha.charlie", OrgId: orgID}, OldPath: "alpha.bravo.charlie", NewPath: "alpha.charlie"},
+			},
+		},
+		{
+			name: "Several changes in a row",
+			change: func(d folder.IDriver) error {
+				if _, err := d.CreateFolder(orgID, "echo", "golf"); err != nil {
+					return err
+				}
+				if _, err := d.CopyFolder(orgID, "alpha.bravo", "echo.golf"); err != nil {
+					return err
+				}
+				if _, _, err := d.DeleteFolder(orgID, "alpha.delta", folder.DeleteRestrict); err != nil {
+					return err
+				}
+				_, err := d.MoveFolder(orgID, "alpha.bravo.charlie", "echo")
+				return err
+			},
+			want: []folder.PathChange{
+				{Folder: folder.Folder{Name: "charlie", Paths: "echo.charlie", OrgId: orgID}, OldPath: "alpha.bravo.charlie", NewPath: "echo.charlie"},
+				{Folder: folder.Folder{Name: "delta", Paths: "alpha.delta", OrgId: orgID}, OldPath: "alpha.delta"},
+				{Folder: folder.Folder{Name: "golf", Paths: "echo.golf", OrgId: orgID}, NewPath: "echo.golf"},
+				{Folder: folder.Folder{Name: "bravo", Paths: "echo.golf.bravo", OrgId: orgID}, NewPath: "echo.golf.bravo"},
+				{Folder: folder.Folder{Name: "charlie", Paths: "echo.golf.bravo.charlie", OrgId: orgID}, NewPath: "echo.golf.bravo.charlie"},
+			},
+		},
+		{
+			name: "Nothing changes",
+			change: func(d folder.IDriver) error {
+				_, err := d.MoveFolder(orgID, "bravo", "alpha")
+				return err
+			},
+			want: []folder.PathChange{},
+		},
+		{
+			name: "Invalid change",
+			change: func(d folder.IDriver) error {
+				_, err := d.MoveFolder(orgID, "alpha", "charlie")
+				return err
+			},
+			wantErr: folder.ErrCycle,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			for _, driver := range []folder.IDriver{folder.NewDriver(folders), folder.NewStatefulDriver(folders)} {
+				changes, err := driver.Preview(test.change)
+
+				assert.ErrorIs(t, err, test.wantErr)
+				assert.Equal(t, test.want, changes)
+				// The driver is never changed
+				assert.Equal(t, folders, driver.GetFoldersByOrgID(orgID))
+			}
+		})
+	}
+}
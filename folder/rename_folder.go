@@ -0,0 +1,65 @@
+package folder
+
+import (
+	"github.com/gofrs/uuid"
+)
+
+// A method to rename the folder at path, the last label of its path and of every child folder path is rewritten.
+// Returns every folder, the renamed ones keep their place in the slice.
+func (f *driver) RenameFolder(orgID uuid.UUID, path string, newName string) ([]Folder, error) {
+	defer f.lockForChange()()
+
+	idx, err := f.lookupPath("rename", orgID, path)
+	if err != nil {
+		return nil, err
+	}
+	folder := f.folders[idx]
+
+	if err := ValidateLabel(newName); err != nil {
+		return nil, newFolderError("rename", folder, err)
+	}
+
+	newPath := newName
+	if parentPath := parentOf(folder.Paths); parentPath != "" {
+		newPath = parentPath + "." + newName
+	}
+
+	// Reject a sibling with the same name (renaming a folder to its own name leaves the structure unchanged)
+	if siblingIdx, exists := f.pathMap[pathKey(orgID, newPath)]; exists && siblingIdx != idx {
+		return nil, newFolderError("rename", folder, ErrNameConflict)
+	}
+
+	// Same prefix rewrite as MoveFolder, the folder itself and all its children
+	renamed := append([]int{idx}, f.subtree(f.nodes[idx], 0)...)
+
+	if f.stateful {
+		f.rename(idx, newName)
+		f.rebase(renamed, folder.Paths, newPath)
+		return append([]Folder(nil), f.folders...), nil
+	}
+
+	newFolders := rebased(f.folders, renamed, folder.Paths, newPath)
+	newFolders[idx].Name = newName
+	return newFolders, nil
+}
+
+// Changes the name of the folder at idx and moves it to the matching name index entry.
+// Must be called with the lock from lockForChange held.
+func (f *driver) rename(idx int, newName string) {
+	folder := f.folders[idx]
+
+	oldKey := nameKey(folder.OrgId, folder.Name)
+	for i, sameName := range f.folderMap[oldKey] {
+		if sameName == idx {
+			f.folderMap[oldKey] = append(f.folderMap[oldKey][:i:i], f.folderMap[oldKey][i+1:]...)
+			break
+		}
+	}
+	if len(f.folderMap[oldKey]) == 0 {
+		delete(f.folderMap, oldKey)
+	}
+
+	newKey := nameKey(folder.OrgId, newName)
+	f.folderMap[newKey] = append(f.folderMap[newKey], idx)
+	f.folders[idx].Name = newName
+}
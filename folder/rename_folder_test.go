@@ -0,0 +1,133 @@
+package folder_test
+
+import (
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_RenameFolder(t *testing.T) {
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "delta", Paths: "alpha.delta", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+	}
+
+	testCases := []struct {
+		name    string
+		orgID   uuid.UUID
+		path    string
+		newName string
+		want    []folder.Folder
+		wantErr error
+	}{
+		{
+			name:    "Rename folder with children",
+			orgID:   uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			path:    "alpha.bravo",
+			newName: "kilo",
+			want: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "kilo", Paths: "alpha.kilo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "charlie", Paths: "alpha.kilo.charlie", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "delta", Paths: "alpha.delta", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+			},
+		},
+		{
+			name:    "Rename root folder",
+			orgID:   uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			path:    "alpha",
+			newName: "omega",
+			want: []folder.Folder{
+				{Name: "omega", Paths: "omega", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "bravo", Paths: "omega.bravo", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "charlie", Paths: "omega.bravo.charlie", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "delta", Paths: "omega.delta", OrgId: uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")},
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+			},
+		},
+		{
+			name:    "Rename to its own name",
+			orgID:   uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			path:    "alpha.delta",
+			newName: "delta",
+			want:    folders,
+		},
+		{
+			name:    "Sibling with the same name",
+			orgID:   uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			path:    "alpha.bravo",
+			newName: "delta",
+			wantErr: folder.ErrNameConflict,
+		},
+		{
+			name:    "Invalid name",
+			orgID:   uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a"),
+			path:    "alpha.bravo",
+			newName: "kilo.lima",
+			wantErr: folder.ErrInvalidLabel,
+		},
+		{
+			name:    "Folder of another organization",
+			orgID:   uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b"),
+			path:    "alpha.delta",
+			newName: "kilo",
+			wantErr: folder.ErrFolderNotFound,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			result, error := folder.NewDriver(folders).RenameFolder(test.orgID, test.path, test.newName)
+			if test.wantErr != nil {
+				assert.ErrorIs(t, error, test.wantErr)
+				return
+			}
+			assert.NoError(t, error)
+			assert.Equal(t, test.want, result)
+
+			// A stateful driver gives the same result and keeps it
+			driver := folder.NewStatefulDriver(folders)
+			result, error = driver.RenameFolder(test.orgID, test.path, test.newName)
+			assert.NoError(t, error)
+			assert.Equal(t, test.want, result)
+			for _, f := range test.want {
+				got, err := driver.GetFolderByPath(f.OrgId, f.Paths)
+				assert.NoError(t, err)
+				assert.Equal(t, f, got)
+			}
+		})
+	}
+}
+
+// The name index follows renames, so bare names can be used right after
+func Test_folder_RenameFolder_Stateful(t *testing.T) {
+	orgID := uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")
+	driver := folder.NewStatefulDriver([]folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: orgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: orgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: orgID},
+		{Name: "golf", Paths: "golf", OrgId: orgID},
+	})
+
+	_, err := driver.RenameFolder(orgID, "alpha.bravo", "kilo")
+	assert.NoError(t, err)
+
+	_, err = driver.GetAllChildFolders(orgID, "bravo")
+	assert.ErrorIs(t, err, folder.ErrFolderNotFound)
+
+	_, err = driver.MoveFolder(orgID, "kilo", "golf")
+	assert.NoError(t, err)
+
+	children, err := driver.GetAllChildFolders(orgID, "golf")
+	assert.NoError(t, err)
+	assert.Equal(t, []folder.Folder{
+		{Name: "kilo", Paths: "golf.kilo", OrgId: orgID},
+		{Name: "charlie", Paths: "golf.kilo.charlie", OrgId: orgID},
+	}, children)
+}
@@ -0,0 +1,182 @@
+// Package sqlitestore is a folder.Store backed by an embedded SQLite database,
+// for local development and integration tests without PostgreSQL.
+package sqlitestore
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	_ "modernc.org/sqlite" // pure Go driver, registered as "sqlite"
+)
+
+// seq keeps the order of the folders, same as the slice index for the in-memory driver.
+// The unique (org_id, paths) index also serves the descendant queries: the paths below "alpha"
+// are the range ["alpha.", "alpha/") since '/' is the character after '.'.
+const schema = `
+CREATE TABLE IF NOT EXISTS folders (
+	seq      INTEGER PRIMARY KEY,
+	name     TEXT NOT NULL,
+	org_id   TEXT NOT NULL,
+	paths    TEXT NOT NULL,
+	position INTEGER NOT NULL DEFAULT 0,
+	UNIQUE (org_id, paths)
+)`
+
+// Store is a folder.Store keeping the folders in a SQLite database.
+// Use it with folder.NewStoreDriver, every change of the driver is applied in a single transaction.
+// Paths are unique within an organization, unlike the folders accepted by folder.NewDriver.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens (or creates) the SQLite database at path and creates the folders table if needed
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Load returns every folder, in the order they were saved or created
+func (s *Store) Load() ([]folder.Folder, error) {
+	return s.query("SELECT name, org_id, paths, position FROM folders ORDER BY seq")
+}
+
+// Save replaces every folder by folders in a single transaction, e.g. to load GetSampleData.
+// It fails, keeping the previous folders, when two folders have the same path in an organization.
+func (s *Store) Save(folders []folder.Folder) error {
+	return s.inTx(func(tx *sql.Tx) error {
+		if _, err := tx.Exec("DELETE FROM folders"); err != nil {
+			return err
+		}
+		insert, err := tx.Prepare("INSERT INTO folders (seq, name, org_id, paths, position) VALUES (?, ?, ?, ?, ?)")
+		if err != nil {
+			return err
+		}
+		defer insert.Close()
+
+		for i, f := range folders {
+			if _, err := insert.Exec(i, f.Name, f.OrgId.String(), f.Paths, f.Position); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Apply applies changes in a single transaction, see folder.Store.
+// Moved folders keep their seq, so the order of the folders doesn't change.
+func (s *Store) Apply(changes []folder.PathChange) error {
+	return s.inTx(func(tx *sql.Tx) error {
+		// Resolve every change against the folders before any of them
+		seqs := make([]int64, len(changes))
+		for i, change := range changes {
+			if change.OldPath == "" {
+				continue
+			}
+			err := tx.QueryRow("SELECT seq FROM folders WHERE org_id = ? AND paths = ?", change.Folder.OrgId.String(), change.OldPath).Scan(&seqs[i])
+			if err == sql.ErrNoRows {
+				return &folder.FolderError{Op: "apply", Name: change.Folder.Name, OrgID: change.Folder.OrgId, Path: change.OldPath, Err: folder.ErrFolderNotFound}
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		// Park the moved folders on a path that can't be a valid ltree path first,
+		// so folders swapping their paths don't break the unique index
+		for i, change := range changes {
+			var err error
+			switch {
+			case change.OldPath == "":
+			case change.NewPath == "":
+				_, err = tx.Exec("DELETE FROM folders WHERE seq = ?", seqs[i])
+			default:
+				_, err = tx.Exec("UPDATE folders SET paths = '#' || seq WHERE seq = ?", seqs[i])
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		for i, change := range changes {
+			f := change.Folder
+			var err error
+			switch {
+			case change.OldPath == "":
+				_, err = tx.Exec("INSERT INTO folders (seq, name, org_id, paths, position) VALUES ((SELECT COALESCE(MAX(seq), -1) + 1 FROM folders), ?, ?, ?, ?)",
+					f.Name, f.OrgId.String(), f.Paths, f.Position)
+			case change.NewPath == "":
+			default:
+				_, err = tx.Exec("UPDATE folders SET name = ?, org_id = ?, paths = ?, position = ? WHERE seq = ?",
+					f.Name, f.OrgId.String(), f.Paths, f.Position, seqs[i])
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ChildFolders returns every folder below the folder at path, in the order they were saved or created.
+// Only reads the index range of the paths below path.
+func (s *Store) ChildFolders(orgID uuid.UUID, path string) ([]folder.Folder, error) {
+	var exists bool
+	if err := s.db.QueryRow("SELECT EXISTS (SELECT 1 FROM folders WHERE org_id = ? AND paths = ?)", orgID.String(), path).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, &folder.FolderError{Op: "get child folders", OrgID: orgID, Path: path, Err: folder.ErrFolderNotFound}
+	}
+
+	return s.query(childFoldersQuery, orgID.String(), path+".", path+"/")
+}
+
+const childFoldersQuery = "SELECT name, org_id, paths, position FROM folders WHERE org_id = ? AND paths >= ? AND paths < ? ORDER BY seq"
+
+func (s *Store) query(query string, args ...any) ([]folder.Folder, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	folders := []folder.Folder{}
+	for rows.Next() {
+		var f folder.Folder
+		var orgID string
+		if err := rows.Scan(&f.Name, &orgID, &f.Paths, &f.Position); err != nil {
+			return nil, err
+		}
+		if f.OrgId, err = uuid.FromString(orgID); err != nil {
+			return nil, fmt.Errorf("folder %s: %w", f.Paths, err)
+		}
+		folders = append(folders, f)
+	}
+	return folders, rows.Err()
+}
+
+// Runs fn in a transaction, committed if fn succeeds and rolled back otherwise
+func (s *Store) inTx(fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
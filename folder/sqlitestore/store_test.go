@@ -0,0 +1,80 @@
+package sqlitestore
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func openTestStore(t *testing.T) *Store {
+	store, err := Open(filepath.Join(t.TempDir(), "folders.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+// The sample data loads in bulk and the descendant query agrees with the driver
+func Test_sqlitestore_ChildFolders(t *testing.T) {
+	folders := folder.GetSampleData()
+	store := openTestStore(t)
+	assert.NoError(t, store.Save(folders))
+
+	loaded, err := store.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, folders, loaded)
+
+	driver := folder.NewDriver(folders)
+	for _, f := range folders[:20] {
+		want, err := driver.GetChildrenByPath(f.OrgId, f.Paths)
+		assert.NoError(t, err)
+
+		got, err := store.ChildFolders(f.OrgId, f.Paths)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, want, got)
+	}
+
+	_, err = store.ChildFolders(uuid.FromStringOrNil(folder.DefaultOrgID), "missing")
+	assert.ErrorIs(t, err, folder.ErrFolderNotFound)
+}
+
+// A folder sharing the prefix of another folder's name is not one of its children
+func Test_sqlitestore_ChildFolders_Prefix(t *testing.T) {
+	orgID := uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")
+	store := openTestStore(t)
+	assert.NoError(t, store.Save([]folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: orgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: orgID},
+		{Name: "alpha-2", Paths: "alpha-2", OrgId: orgID},
+		{Name: "charlie", Paths: "alpha-2.charlie", OrgId: orgID},
+		{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+		{Name: "delta", Paths: "alpha.delta", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+	}))
+
+	children, err := store.ChildFolders(orgID, "alpha")
+	assert.NoError(t, err)
+	assert.Equal(t, []folder.Folder{{Name: "bravo", Paths: "alpha.bravo", OrgId: orgID}}, children)
+}
+
+// The descendant query is a range scan of the (org_id, paths) index, not a full table scan
+func Test_sqlitestore_ChildFolders_Index(t *testing.T) {
+	store := openTestStore(t)
+
+	rows, err := store.db.Query("EXPLAIN QUERY PLAN "+childFoldersQuery, folder.DefaultOrgID, "alpha.", "alpha/")
+	assert.NoError(t, err)
+	defer rows.Close()
+
+	plan := []string{}
+	for rows.Next() {
+		var id, parent, notUsed int
+		var detail string
+		assert.NoError(t, rows.Scan(&id, &parent, &notUsed, &detail))
+		plan = append(plan, detail)
+	}
+	assert.Contains(t, strings.Join(plan, "\n"), "USING INDEX sqlite_autoindex_folders_1 (org_id=? AND paths>? AND paths<?)")
+}
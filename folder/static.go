@@ -3,11 +3,7 @@ package folder
 import (
 	"encoding/json"
 	"fmt"
-	"io"
-	"io/fs"
-	"os"
-	"path/filepath"
-	"runtime"
+	"math/rand"
 
 	"github.com/gofrs/uuid"
 	"github.com/lucasepe/codename"
@@ -33,23 +29,35 @@ type Folder struct {
 	Name  string    `json:"name"`
 	OrgId uuid.UUID `json:"org_id"`
 	Paths string    `json:"paths"`
+	// Position orders a folder among its siblings, folders with the same position keep their slice order
+	Position int `json:"position,omitempty"`
 }
 
 func GenerateData() []Folder {
 	rng, _ := codename.DefaultRNG()
+	return generateData(rng)
+}
+
+// GenerateDataWithSeed is GenerateData with a fixed seed,
+// the names, org IDs and shape of the trees are the same for every call with the same seed.
+func GenerateDataWithSeed(seed int64) []Folder {
+	return generateData(rand.New(rand.NewSource(seed)))
+}
+
+func generateData(rng *rand.Rand) []Folder {
 	tree := []Folder{}
 
 	for i := 0; i < MaxRootSet; i++ {
 		orgId := uuid.FromStringOrNil(DefaultOrgID)
 		if i%3 == 0 {
-			orgId = uuid.Must(uuid.NewV4())
+			orgId = newOrgID(rng)
 		}
 
 		name := codename.Generate(rng, 0)
 
 		subtree := make(chan []Folder)
 		go func() {
-			subtree <- generateTree(1, []Folder{
+			subtree <- generateTree(rng, 1, []Folder{
 				{
 					Name:  name,
 					OrgId: orgId,
@@ -63,9 +71,7 @@ func GenerateData() []Folder {
 	return tree
 }
 
-func generateTree(depth int, tree []Folder) []Folder {
-	rng, _ := codename.DefaultRNG()
-
+func generateTree(rng *rand.Rand, depth int, tree []Folder) []Folder {
 	if depth >= MaxDepth {
 		return tree
 	}
@@ -77,7 +83,7 @@ func generateTree(depth int, tree []Folder) []Folder {
 
 			childTree := make(chan []Folder)
 			go func() {
-				childTree <- generateTree(depth+1, []Folder{
+				childTree <- generateTree(rng, depth+1, []Folder{
 					{
 						Name:  name,
 						OrgId: t.OrgId,
@@ -92,6 +98,15 @@ func generateTree(depth int, tree []Folder) []Folder {
 	return tree
 }
 
+// Returns a v4 UUID read from rng instead of crypto/rand, so seeded data also gets the same org IDs
+func newOrgID(rng *rand.Rand) uuid.UUID {
+	var orgID uuid.UUID
+	rng.Read(orgID[:])
+	orgID.SetVersion(uuid.V4)
+	orgID.SetVariant(uuid.VariantRFC4122)
+	return orgID
+}
+
 func MarshalJson(b interface{}) []byte {
 	s, _ := json.MarshalIndent(b, "", "\t")
 
@@ -103,45 +118,20 @@ func PrettyPrint(b interface{}) {
 	fmt.Print(string(s))
 }
 
+// GetSampleData loads the folders of SampleDataPath and panics if they can't be read,
+// use LoadFolders to handle the error instead.
 func GetSampleData() []Folder {
-	_, filename, _, _ := runtime.Caller(0)
-	fmt.Println(filename)
-	basePath := filepath.Dir(filename)
-	filePath := filepath.Join(basePath, "sample.json")
-
-	fmt.Println(filePath)
-
-	file, err := os.Open(filePath)
-	if err != nil {
-		panic(err)
-	}
-	defer file.Close()
-
-	jsonByte, err := io.ReadAll(file)
+	folders, err := LoadFolders(SampleDataPath())
 	if err != nil {
 		panic(err)
 	}
-
-	folders := []Folder{}
-	err = json.Unmarshal(jsonByte, &folders)
-	if err != nil {
-		panic(err)
-	}
-
 	return folders
 }
 
+// WriteSampleData writes data to SampleDataPath and panics if it can't be written,
+// use SaveFolders to handle the error instead.
 func WriteSampleData(data interface{}) {
-	b := MarshalJson(data)
-	_, filename, _, _ := runtime.Caller(0)
-	fmt.Println(filename)
-	basePath := filepath.Dir(filename)
-	filePath := filepath.Join(basePath, "sample.json")
-
-	fmt.Println(filePath)
-
-	err := os.WriteFile(filePath, b, fs.ModeType)
-	if err != nil {
+	if err := SaveFolders(SampleDataPath(), data); err != nil {
 		panic(err)
 	}
 }
@@ -0,0 +1,179 @@
+package folder
+
+import (
+	"sync"
+
+	"github.com/gofrs/uuid"
+)
+
+// Store persists the folder structure of a driver created with NewStoreDriver.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Load returns every stored folder.
+	Load() ([]Folder, error)
+	// Save replaces every stored folder by folders. A store may reject folders the in-memory driver
+	// accepts, e.g. sqlitestore.Store fails on two folders with the same path in an organization.
+	Save(folders []Folder) error
+	// Apply applies changes in a single transaction, either all of them or none:
+	// an empty OldPath creates change.Folder, an empty NewPath removes the folder at OldPath,
+	// otherwise the folder at OldPath is replaced by change.Folder.
+	// OldPath always refers to the stored folders before any of the changes.
+	Apply(changes []PathChange) error
+}
+
+// storeDriver is a stateful driver that writes every change to a Store before keeping it.
+// Queries are answered from memory by the embedded driver.
+type storeDriver struct {
+	*driver
+	store   Store
+	writeMu sync.Mutex // serializes changes between the dry run and the adoption of its result
+}
+
+// NewStoreDriver returns a stateful driver loaded from store. Every successful change is applied to
+// store first and only kept by the driver once the store accepted it, so the two never disagree.
+// The driver assumes it is the only writer of store. The driver never creates two folders with
+// the same path, so a store requiring unique paths only has to reject them in Save.
+func NewStoreDriver(store Store) (IDriver, error) {
+	folders, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	f := &driver{folders: append([]Folder(nil), folders...), stateful: true}
+	f.reindex()
+	return &storeDriver{driver: f, store: store}, nil
+}
+
+// Runs change on a copy of the driver, applies the folders it changed to the store and keeps the copy
+func (s *storeDriver) apply(change func(d IDriver) error) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	before, preview, err := s.dryRun(change)
+	if err != nil {
+		return err
+	}
+	if changes := pathChanges(before, preview, true); len(changes) > 0 {
+		if err := s.store.Apply(changes); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.adopt(preview)
+	return nil
+}
+
+func (s *storeDriver) MoveFolder(orgID uuid.UUID, name string, dst string, opts ...MoveOption) (res []Folder, err error) {
+	err = s.apply(func(d IDriver) error {
+		res, err = d.MoveFolder(orgID, name, dst, opts...)
+		return err
+	})
+	return res, err
+}
+
+func (s *storeDriver) MoveFolderByPath(orgID uuid.UUID, src string, dst string, opts ...MoveOption) (res []Folder, err error) {
+	err = s.apply(func(d IDriver) error {
+		res, err = d.MoveFolderByPath(orgID, src, dst, opts...)
+		return err
+	})
+	return res, err
+}
+
+func (s *storeDriver) MoveFolderToRoot(orgID uuid.UUID, name string) (res []Folder, err error) {
+	err = s.apply(func(d IDriver) error {
+		res, err = d.MoveFolderToRoot(orgID, name)
+		return err
+	})
+	return res, err
+}
+
+func (s *storeDriver) MoveFolders(orgID uuid.UUID, requests []MoveRequest) (res []Folder, err error) {
+	err = s.apply(func(d IDriver) error {
+		res, err = d.MoveFolders(orgID, requests)
+		return err
+	})
+	return res, err
+}
+
+func (s *storeDriver) CreateFolder(orgID uuid.UUID, parentPath string, name string) (res []Folder, err error) {
+	err = s.apply(func(d IDriver) error {
+		res, err = d.CreateFolder(orgID, parentPath, name)
+		return err
+	})
+	return res, err
+}
+
+func (s *storeDriver) RenameFolder(orgID uuid.UUID, path string, newName string) (res []Folder, err error) {
+	err = s.apply(func(d IDriver) error {
+		res, err = d.RenameFolder(orgID, path, newName)
+		return err
+	})
+	return res, err
+}
+
+func (s *storeDriver) CopyFolder(orgID uuid.UUID, srcPath string, dstPath string, opts ...CopyOption) (res []Folder, err error) {
+	err = s.apply(func(d IDriver) error {
+		res, err = d.CopyFolder(orgID, srcPath, dstPath, opts...)
+		return err
+	})
+	return res, err
+}
+
+func (s *storeDriver) MergeFolders(orgID uuid.UUID, srcPath string, dstPath string, policy CollisionPolicy) (res []Folder, conflicts []PathChange, err error) {
+	err = s.apply(func(d IDriver) error {
+		res, conflicts, err = d.MergeFolders(orgID, srcPath, dstPath, policy)
+		return err
+	})
+	return res, conflicts, err
+}
+
+func (s *storeDriver) DeleteFolder(orgID uuid.UUID, path string, mode DeleteMode) (removed []Folder, res []Folder, err error) {
+	err = s.apply(func(d IDriver) error {
+		removed, res, err = d.DeleteFolder(orgID, path, mode)
+		return err
+	})
+	return removed, res, err
+}
+
+// Returns folders with changes applied, see Store.Apply. folders is left untouched.
+// Fails with ErrFolderNotFound if a change refers to a folder that doesn't exist.
+func applyChanges(folders []Folder, changes []PathChange) ([]Folder, error) {
+	pathMap := make(map[string]int, len(folders))
+	for i, folder := range folders {
+		pathMap[pathKey(folder.OrgId, folder.Paths)] = i
+	}
+
+	// Resolve every change against the folders before any of them
+	updated := map[int]Folder{}
+	removed := map[int]bool{}
+	created := []Folder{}
+	for _, change := range changes {
+		if change.OldPath == "" {
+			created = append(created, change.Folder)
+			continue
+		}
+		idx, exists := pathMap[pathKey(change.Folder.OrgId, change.OldPath)]
+		if !exists {
+			return nil, &FolderError{Op: "apply", Name: change.Folder.Name, OrgID: change.Folder.OrgId, Path: change.OldPath, Err: ErrFolderNotFound}
+		}
+		if change.NewPath == "" {
+			removed[idx] = true
+		} else {
+			updated[idx] = change.Folder
+		}
+	}
+
+	newFolders := make([]Folder, 0, len(folders)-len(removed)+len(created))
+	for i, folder := range folders {
+		if removed[i] {
+			continue
+		}
+		if newFolder, exists := updated[i]; exists {
+			folder = newFolder
+		}
+		newFolders = append(newFolders, folder)
+	}
+	return append(newFolders, created...), nil
+}
@@ -0,0 +1,235 @@
+package folder_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/georgechieng-sc/interns-2022/folder/sqlitestore"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// Shared by the tests of every Store implementation, newStore must return an empty store
+func testStore(t *testing.T, newStore func(t *testing.T) folder.Store) {
+	orgID := uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: orgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: orgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: orgID},
+		{Name: "delta", Paths: "delta", OrgId: orgID},
+		{Name: "golf", Paths: "golf", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+	}
+
+	t.Run("Save and load", func(t *testing.T) {
+		store := newStore(t)
+		loaded, err := store.Load()
+		assert.NoError(t, err)
+		assert.Empty(t, loaded)
+
+		assert.NoError(t, store.Save(folders))
+		loaded, err = store.Load()
+		assert.NoError(t, err)
+		assert.Equal(t, folders, loaded)
+	})
+
+	t.Run("Apply changes", func(t *testing.T) {
+		store := newStore(t)
+		assert.NoError(t, store.Save(folders))
+
+		assert.NoError(t, store.Apply([]folder.PathChange{
+			// Swapped names only work if every OldPath refers to the folders before the changes
+			{Folder: folder.Folder{Name: "delta", Paths: "alpha.delta", OrgId: orgID}, OldPath: "alpha.bravo", NewPath: "alpha.delta"},
+			{Folder: folder.Folder{Name: "bravo", Paths: "bravo", OrgId: orgID}, OldPath: "delta", NewPath: "bravo"},
+			{Folder: folder.Folder{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: orgID}, OldPath: "alpha.bravo.charlie"},
+			{Folder: folder.Folder{Name: "alpha", Paths: "alpha", OrgId: orgID, Position: 2}, OldPath: "alpha", NewPath: "alpha"},
+			{Folder: folder.Folder{Name: "hotel", Paths: "golf.hotel", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")}, NewPath: "golf.hotel"},
+		}))
+
+		loaded, err := store.Load()
+		assert.NoError(t, err)
+		assert.Equal(t, []folder.Folder{
+			{Name: "alpha", Paths: "alpha", OrgId: orgID, Position: 2},
+			{Name: "delta", Paths: "alpha.delta", OrgId: orgID},
+			{Name: "bravo", Paths: "bravo", OrgId: orgID},
+			{Name: "golf", Paths: "golf", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+			{Name: "hotel", Paths: "golf.hotel", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+		}, loaded)
+	})
+
+	t.Run("Apply is all or nothing", func(t *testing.T) {
+		store := newStore(t)
+		assert.NoError(t, store.Save(folders))
+
+		err := store.Apply([]folder.PathChange{
+			{Folder: folder.Folder{Name: "delta", Paths: "alpha.delta", OrgId: orgID}, OldPath: "delta", NewPath: "alpha.delta"},
+			{Folder: folder.Folder{Name: "zulu", Paths: "zulu", OrgId: orgID}, OldPath: "zulu"},
+		})
+		assert.ErrorIs(t, err, folder.ErrFolderNotFound)
+
+		loaded, err := store.Load()
+		assert.NoError(t, err)
+		assert.Equal(t, folders, loaded)
+	})
+
+	t.Run("Driver", func(t *testing.T) {
+		store := newStore(t)
+		assert.NoError(t, store.Save(folders))
+
+		driver, err := folder.NewStoreDriver(store)
+		assert.NoError(t, err)
+
+		_, err = driver.MoveFolder(orgID, "bravo", "delta", folder.MoveToIndex(0))
+		assert.NoError(t, err)
+		_, err = driver.CreateFolder(orgID, "delta", "echo")
+		assert.NoError(t, err)
+		_, err = driver.RenameFolder(orgID, "delta.bravo", "foxtrot")
+		assert.NoError(t, err)
+		_, err = driver.CopyFolder(orgID, "delta.foxtrot", "alpha")
+		assert.NoError(t, err)
+		_, _, err = driver.DeleteFolder(orgID, "delta.foxtrot.charlie", folder.DeleteRestrict)
+		assert.NoError(t, err)
+		// A failed change doesn't reach the store
+		_, err = driver.MoveFolder(orgID, "delta", "echo")
+		assert.ErrorIs(t, err, folder.ErrCycle)
+
+		want := []folder.Folder{
+			{Name: "alpha", Paths: "alpha", OrgId: orgID},
+			{Name: "foxtrot", Paths: "delta.foxtrot", OrgId: orgID},
+			{Name: "delta", Paths: "delta", OrgId: orgID},
+			{Name: "golf", Paths: "golf", OrgId: uuid.FromStringOrNil("b1234567-b7c0-45a3-a6ae-9546248fb17b")},
+			{Name: "echo", Paths: "delta.echo", OrgId: orgID},
+			{Name: "foxtrot", Paths: "alpha.foxtrot", OrgId: orgID},
+			{Name: "charlie", Paths: "alpha.foxtrot.charlie", OrgId: orgID},
+		}
+		loaded, err := store.Load()
+		assert.NoError(t, err)
+		assert.Equal(t, want, loaded)
+
+		// A new driver on the same store sees the same folders
+		reloaded, err := folder.NewStoreDriver(store)
+		assert.NoError(t, err)
+		assert.Equal(t, driver.GetFoldersByOrgID(orgID), reloaded.GetFoldersByOrgID(orgID))
+	})
+}
+
+func Test_folder_MemoryStore(t *testing.T) {
+	testStore(t, func(t *testing.T) folder.Store {
+		return folder.NewMemoryStore(nil)
+	})
+}
+
+func Test_folder_JSONFileStore(t *testing.T) {
+	testStore(t, func(t *testing.T) folder.Store {
+		return folder.NewJSONFileStore(filepath.Join(t.TempDir(), "folders.json"))
+	})
+
+	t.Run("Invalid file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "folders.json")
+		assert.NoError(t, os.WriteFile(path, []byte("not json"), 0644))
+
+		_, err := folder.NewStoreDriver(folder.NewJSONFileStore(path))
+		assert.Error(t, err)
+	})
+}
+
+func Test_folder_SQLiteStore(t *testing.T) {
+	newStore := func(t *testing.T) *sqlitestore.Store {
+		store, err := sqlitestore.Open(filepath.Join(t.TempDir(), "folders.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { store.Close() })
+		return store
+	}
+	testStore(t, func(t *testing.T) folder.Store {
+		return newStore(t)
+	})
+
+	// The range query on the path index finds the same folders as the driver, before and after moves
+	t.Run("Child folders", func(t *testing.T) {
+		assertChildFolders := func(t *testing.T, store *sqlitestore.Store, driver folder.IDriver, folders []folder.Folder) {
+			for _, f := range folders {
+				want, err := driver.GetChildrenByPath(f.OrgId, f.Paths)
+				assert.NoError(t, err)
+				got, err := store.ChildFolders(f.OrgId, f.Paths)
+				assert.NoError(t, err)
+				assert.ElementsMatch(t, want, got, f.Paths)
+			}
+		}
+
+		for name, folders := range map[string][]folder.Folder{
+			"sample data":   folder.GetSampleData(),
+			"generate seed": folder.GenerateDataWithSeed(1),
+		} {
+			t.Run(name, func(t *testing.T) {
+				store := newStore(t)
+				assert.NoError(t, store.Save(folders))
+				driver, err := folder.NewStoreDriver(store)
+				assert.NoError(t, err)
+				assertChildFolders(t, store, driver, folders)
+
+				// Move every root below the next one, each move rewrites a whole subtree
+				var roots []folder.Folder
+				for _, f := range driver.GetFoldersByOrgID(uuid.FromStringOrNil(folder.DefaultOrgID)) {
+					if !strings.Contains(f.Paths, ".") {
+						roots = append(roots, f)
+					}
+				}
+				assert.NotEmpty(t, roots)
+				for i := 0; i+1 < len(roots) && i < 10; i += 2 {
+					_, err := driver.MoveFolderByPath(roots[i].OrgId, roots[i].Paths, roots[i+1].Paths)
+					assert.NoError(t, err)
+				}
+				loaded, err := store.Load()
+				assert.NoError(t, err)
+				assertChildFolders(t, store, driver, loaded)
+			})
+		}
+	})
+
+	// The unique (org_id, paths) index rejects folders the in-memory driver accepts
+	t.Run("Duplicate paths", func(t *testing.T) {
+		orgID := uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")
+		folders := []folder.Folder{
+			{Name: "alpha", Paths: "alpha", OrgId: orgID},
+			{Name: "alpha", Paths: "alpha", OrgId: orgID},
+		}
+		assert.Equal(t, folders, folder.NewDriver(folders).GetFoldersByOrgID(orgID))
+
+		store := newStore(t)
+		assert.Error(t, store.Save(folders))
+		loaded, err := store.Load()
+		assert.NoError(t, err)
+		assert.Empty(t, loaded)
+	})
+}
+
+// Store whose Apply always fails
+type failingStore struct {
+	folder.Store
+}
+
+var errStoreDown = errors.New("store is down")
+
+func (s failingStore) Apply(changes []folder.PathChange) error {
+	return errStoreDown
+}
+
+// The driver is left unchanged when the store rejects a change
+func Test_folder_StoreDriver_Failure(t *testing.T) {
+	orgID := uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: orgID},
+		{Name: "bravo", Paths: "bravo", OrgId: orgID},
+	}
+	driver, err := folder.NewStoreDriver(failingStore{folder.NewMemoryStore(folders)})
+	assert.NoError(t, err)
+
+	_, err = driver.MoveFolder(orgID, "bravo", "alpha")
+	assert.ErrorIs(t, err, errStoreDown)
+	assert.Equal(t, folders, driver.GetFoldersByOrgID(orgID))
+}
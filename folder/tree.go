@@ -0,0 +1,110 @@
+package folder
+
+import (
+	"sort"
+	"strings"
+)
+
+// node is a folder in the tree index built by reindex.
+// idx points back into driver.folders, children are kept in sibling order (see lessNode).
+type node struct {
+	idx      int
+	parent   *node
+	children []*node
+}
+
+// Builds the parent -> children adjacency for f.folders, pathMap must already be up to date.
+// Folders whose parent path is missing are linked to their closest existing ancestor, or become roots.
+func (f *driver) buildTree() {
+	nodes := make([]*node, len(f.folders))
+	for i := range f.folders {
+		nodes[i] = &node{idx: i}
+	}
+
+	for i, folder := range f.folders {
+		path := folder.Paths
+		for lastDot := strings.LastIndex(path, "."); lastDot != -1; lastDot = strings.LastIndex(path, ".") {
+			path = path[:lastDot]
+			if parentIdx, exists := f.pathMap[pathKey(folder.OrgId, path)]; exists {
+				nodes[i].parent = nodes[parentIdx]
+				nodes[parentIdx].children = append(nodes[parentIdx].children, nodes[i])
+				break
+			}
+		}
+	}
+
+	f.nodes = nodes
+	for _, n := range nodes {
+		f.sortChildren(n)
+	}
+}
+
+// Sibling order: by Position, folders with the same Position keep the order of f.folders
+func (f *driver) lessNode(a, b *node) bool {
+	posA, posB := f.folders[a.idx].Position, f.folders[b.idx].Position
+	if posA != posB {
+		return posA < posB
+	}
+	return a.idx < b.idx
+}
+
+func (f *driver) sortChildren(n *node) {
+	sort.Slice(n.children, func(i, j int) bool {
+		return f.lessNode(n.children[i], n.children[j])
+	})
+}
+
+// Returns the indexes of every folder below n, at most maxDepth levels deep (0 means no limit).
+// The result is depth first, every folder is followed by its children in sibling order.
+func (f *driver) subtree(n *node, maxDepth int) []int {
+	baseDepth := folderDepth(f.folders[n.idx].Paths)
+
+	var res []int
+	var stack []*node
+	push := func(children []*node) {
+		// Pushed in reverse so the first sibling is popped first
+		for i := len(children) - 1; i >= 0; i-- {
+			stack = append(stack, children[i])
+		}
+	}
+
+	push(n.children)
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if maxDepth > 0 && folderDepth(f.folders[current.idx].Paths)-baseDepth > maxDepth {
+			continue
+		}
+		res = append(res, current.idx)
+		push(current.children)
+	}
+
+	return res
+}
+
+// Detaches n from its current parent and links it below newParent in sibling order,
+// a nil newParent makes n a root
+func (f *driver) relink(n *node, newParent *node) {
+	if n.parent != nil {
+		siblings := n.parent.children
+		for i, sibling := range siblings {
+			if sibling == n {
+				n.parent.children = append(siblings[:i:i], siblings[i+1:]...)
+				break
+			}
+		}
+	}
+
+	n.parent = newParent
+	if newParent == nil {
+		return
+	}
+
+	i := sort.Search(len(newParent.children), func(i int) bool {
+		return f.lessNode(n, newParent.children[i])
+	})
+	newParent.children = append(newParent.children, nil)
+	copy(newParent.children[i+1:], newParent.children[i:])
+	newParent.children[i] = n
+}
@@ -0,0 +1,110 @@
+package folder_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// The linear scan GetAllChildFolders used before the tree index, kept as a reference
+func linearChildFolders(folders []folder.Folder, parent folder.Folder) []folder.Folder {
+	res := []folder.Folder{}
+	for _, f := range folders {
+		if f.OrgId == parent.OrgId && len(f.Paths) > len(parent.Paths) &&
+			f.Paths[len(parent.Paths)] == '.' && f.Paths[:len(parent.Paths)] == parent.Paths {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+// The tree index must return exactly what the linear scan returns, for every folder of the sample data
+func Test_folder_TreeIndex_MatchesLinearScan(t *testing.T) {
+	folders := folder.GetSampleData()
+	driver := folder.NewDriver(folders)
+
+	for _, f := range folders {
+		children, err := driver.GetChildrenByPath(f.OrgId, f.Paths)
+		assert.NoError(t, err)
+		assert.Equal(t, linearChildFolders(folders, f), children, f.Paths)
+	}
+}
+
+// A folder whose parent is missing still shows up below its closest ancestor
+func Test_folder_TreeIndex_MissingParent(t *testing.T) {
+	orgID := uuid.FromStringOrNil("a1234567-b7c0-45a3-a6ae-9546248fb17a")
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: orgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: orgID},
+		{Name: "golf", Paths: "golf", OrgId: orgID},
+	}
+
+	driver := folder.NewStatefulDriver(folders)
+	children, err := driver.GetAllChildFolders(orgID, "alpha")
+	assert.NoError(t, err)
+	assert.Equal(t, folders[1:2], children)
+
+	_, err = driver.MoveFolder(orgID, "alpha", "golf")
+	assert.NoError(t, err)
+	children, err = driver.GetAllChildFolders(orgID, "golf")
+	assert.NoError(t, err)
+	assert.Equal(t, []folder.Folder{
+		{Name: "alpha", Paths: "golf.alpha", OrgId: orgID},
+		{Name: "charlie", Paths: "golf.alpha.bravo.charlie", OrgId: orgID},
+	}, children)
+}
+
+// go test -run xxx -bench TreeIndex -benchmem ./folder
+func Benchmark_folder_TreeIndex_GetAllChildFolders(b *testing.B) {
+	folders := benchmarkFolders(b, 100_000)
+	parent, _ := benchmarkMove(folders)
+	driver := folder.NewDriver(folders)
+
+	b.Run("index", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := driver.GetChildrenByPath(parent.OrgId, parent.Paths); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("linear scan", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			linearChildFolders(folders, parent)
+		}
+	})
+}
+
+func Benchmark_folder_TreeIndex_MoveFolder(b *testing.B) {
+	folders := benchmarkFolders(b, 100_000)
+	src, dst := benchmarkMove(folders)
+	srcParent := src.Paths[:strings.LastIndex(src.Paths, ".")]
+
+	b.Run("stateless", func(b *testing.B) {
+		driver := folder.NewDriver(folders)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := driver.MoveFolderByPath(src.OrgId, src.Paths, dst.Paths); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	// Moving back and forth only relinks the node and rewrites the moved subtree
+	b.Run("stateful", func(b *testing.B) {
+		driver := folder.NewStatefulDriver(folders)
+		moved := dst.Paths + "." + src.Name
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := driver.MoveFolderByPath(src.OrgId, src.Paths, dst.Paths); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := driver.MoveFolderByPath(src.OrgId, moved, srcParent); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -1,16 +1,25 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"os"
 
 	"github.com/georgechieng-sc/interns-2022/folder"
 	"github.com/gofrs/uuid"
 )
 
 func main() {
+	dataPath := flag.String("data", folder.SampleDataPath(), "JSON file with the folders, defaults to $"+folder.SampleDataEnv+" or folder/sample.json")
+	flag.Parse()
+
 	orgID := uuid.FromStringOrNil(folder.DefaultOrgID)
 
-	res := folder.GetAllFolders()
+	res, err := folder.LoadFolders(*dataPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 
 	// example usage
 	folderDriver := folder.NewDriver(res)